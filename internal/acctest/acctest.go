@@ -1,10 +1,18 @@
 package acctest
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
 
 	"github.com/wordcollector/terraform-provider-fauna/internal/provider"
 )
@@ -19,8 +27,39 @@ func init() {
 	}
 }
 
+// TestAccClient returns the Fauna client of the configured test provider.
+func TestAccClient() *f.FaunaClient {
+	return TestAccProvider.Meta().(*client.Client).FaunaClient
+}
+
 func TestAccPreCheck(t *testing.T) {
 	if v := os.Getenv("FAUNA_SECRET"); v == "" {
 		t.Fatal("'FAUNA_SECRET' must be set for acceptance tests.")
 	}
 }
+
+// TestAccCheckPropertiesRemoved verifies that the Fauna object backing the resource `resourceName`
+// no longer holds any of the given dot-separated property paths.
+func TestAccCheckPropertiesRemoved(resourceName string, ref func(name any) f.Expr, paths ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		res, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		name := res.Primary.Attributes["name"]
+
+		obj, err := TestAccClient().Query(f.Get(ref(name)))
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			if value, err := obj.At(f.ObjKey(strings.Split(path, ".")...)).GetValue(); err == nil && value != (f.NullV{}) {
+				return fmt.Errorf("Property '%s' of '%s' still exists.", path, resourceName)
+			}
+		}
+
+		return nil
+	}
+}
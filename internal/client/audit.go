@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// AuditRecord is a line of the audit log, describing a query issued to create, update or delete a
+// resource.
+type AuditRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	// The Fauna object, identified as by Request.Object.
+	Object    string `json:"object"`
+	Operation string `json:"operation"`
+	// The slash-separated path of the database the query was sent to, `/` for the database the key
+	// was created in.
+	Database string `json:"database"`
+	// The query, with secrets masked as by MaskExpr.
+	FQL string `json:"fql"`
+	// The timestamp of the object the query returned, if any, in microseconds since the epoch.
+	TS       int64  `json:"ts,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// AuditLog appends a record of every query that modifies a resource to a file.
+type AuditLog struct {
+	mu       sync.Mutex
+	file     *os.File
+	operator string
+}
+
+// OpenAuditLog opens the audit log at `path`, creating it if it does not exist, whose records are
+// attributed to `operator`.
+func OpenAuditLog(path string, operator string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open the audit log '%s': %s", path, err)
+	}
+
+	return &AuditLog{file: file, operator: operator}, nil
+}
+
+// Append writes `record` to the log, and flushes it to disk before returning, so that records
+// survive runs that fail or are interrupted.
+func (log *AuditLog) Append(record AuditRecord) error {
+	record.Operator = log.operator
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	if _, err := log.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("Failed to write to the audit log '%s': %s", log.file.Name(), err)
+	}
+
+	if err := log.file.Sync(); err != nil {
+		return fmt.Errorf("Failed to flush the audit log '%s': %s", log.file.Name(), err)
+	}
+
+	return nil
+}
+
+// The FQL functions that write to the database, as named in the JSON sent to Fauna.
+var writeFunctions = map[string]bool{
+	"create": true, "create_access_provider": true, "create_class": true, "create_collection": true,
+	"create_database": true, "create_function": true, "create_index": true, "create_key": true,
+	"create_role": true, "delete": true, "insert": true, "move_database": true, "remove": true,
+	"replace": true, "update": true,
+}
+
+// audit records a query that writes to the database on behalf of the resource operation carried
+// by `ctx`. The reads issued while creating, updating or deleting a resource, such as waiting for
+// an index to be built, are not recorded.
+func (client *Client) audit(ctx context.Context, expr f.Expr, res f.Value, err error) {
+	request, ok := RequestFromContext(ctx)
+	if client.config.AuditLog == nil || !ok || !writes(expr) {
+		return
+	}
+
+	database := client.Database()
+	if database == "" {
+		database = "/"
+	}
+
+	record := AuditRecord{
+		Timestamp: time.Now().UTC(),
+		Object:    request.Object(),
+		Operation: string(request.Operation),
+		Database:  database,
+		FQL:       MaskExpr(expr),
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+	} else if res != nil {
+		res.At(f.ObjKey("ts")).Get(&record.TS)
+	}
+
+	// The query has been applied regardless, so failing to record it does not fail the query.
+	if err := client.config.AuditLog.Append(record); err != nil {
+		tflog.Error(ctx, err.Error())
+	}
+}
+
+// writes reports whether `expr` calls any of the functions that write to the database. An
+// expression that cannot be rendered is assumed to write.
+func writes(expr f.Expr) bool {
+	rendered, err := json.Marshal(expr)
+	if err != nil {
+		return true
+	}
+
+	var decoded any
+	if err := json.Unmarshal(rendered, &decoded); err != nil {
+		return true
+	}
+
+	return callsWriteFunction(decoded)
+}
+
+func callsWriteFunction(value any) bool {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, child := range value {
+			// The keys of object literals are data rather than functions.
+			if key == "object" {
+				if fields, ok := child.(map[string]any); ok {
+					for _, field := range fields {
+						if callsWriteFunction(field) {
+							return true
+						}
+					}
+					continue
+				}
+			}
+
+			if writeFunctions[key] || callsWriteFunction(child) {
+				return true
+			}
+		}
+	case []any:
+		for _, child := range value {
+			if callsWriteFunction(child) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+func TestClientQuery_audit(t *testing.T) {
+	fail := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": [{"code": "validation failed", "description": "Validation failed."}]}`))
+			return
+		}
+
+		w.Write([]byte(`{"resource": {"name": "users", "ts": 1700000000000000}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	auditLog, err := client.OpenAuditLog(path, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn := client.New(client.Config{Secret: "secret", Endpoint: server.URL, HTTP: server.Client(), Database: "app", AuditLog: auditLog})
+
+	request := func(operation client.Operation) context.Context {
+		return client.WithRequest(context.Background(), client.Request{ResourceType: "fauna_collection", ObjectName: "users", Operation: operation})
+	}
+
+	if _, err := conn.Query(request(client.OperationCreate), f.CreateCollection(f.Obj{"name": "users"})); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads are not recorded, including those issued while creating a resource, and those whose
+	// object literals have keys named like functions that write.
+	reads := []f.Expr{
+		f.Exists(f.Collection("users")),
+		f.Select("delete", f.Obj{"delete": f.Get(f.Collection("users"))}),
+	}
+	for _, read := range reads {
+		if _, err := conn.Query(request(client.OperationCreate), read); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := conn.Query(request(client.OperationRead), f.Get(f.Collection("users"))); err != nil {
+		t.Fatal(err)
+	}
+
+	fail = true
+	if _, err := conn.Query(request(client.OperationUpdate), f.Update(f.Collection("users"), f.Obj{"history_days": -1})); err == nil {
+		t.Fatal("Expected the query to fail.")
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected a record for each mutating query, but got: %s", contents)
+	}
+
+	var created, updated client.AuditRecord
+	if err := json.Unmarshal([]byte(lines[0]), &created); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &updated); err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Object != "fauna_collection.users" || created.Operation != "create" || created.Database != "app" || created.Operator != "alice" || created.TS != 1700000000000000 || !strings.Contains(created.FQL, "create_collection") {
+		t.Errorf("Unexpected record of the creation: %+v", created)
+	}
+
+	if updated.Operation != "update" || !strings.Contains(updated.Error, "Validation failed") || updated.TS != 0 {
+		t.Errorf("Unexpected record of the failed update: %+v", updated)
+	}
+}
@@ -0,0 +1,234 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/net/http2"
+)
+
+// The header carrying query tags.
+const headerTags = "x-fauna-tags"
+
+// Config holds the settings used to create a Client.
+type Config struct {
+	Secret string
+	// The URL of the Fauna endpoint. Empty means the client default.
+	Endpoint string
+	// The HTTP client queries are sent with. Nil means an HTTP client like the Fauna client's default.
+	HTTP *http.Client
+	// The server-side timeout of every query. Zero means the client default.
+	QueryTimeout time.Duration
+	// Headers sent with every query.
+	Headers map[string]string
+	// Query tags attached to every query, in addition to those identifying the resource operation.
+	QueryTags map[string]string
+	// Metadata merged into the `data` of every resource.
+	DefaultData map[string]any
+	// The slash-separated path of the database the secret is scoped to, if any.
+	Database string
+	// The role the secret is scoped with, if any.
+	Role string
+	// Whether resources may only be read.
+	ReadOnly bool
+	// Whether plans with pending changes fail when the provider is read-only.
+	ReadOnlyPlanCheck bool
+	// The databases that resources may be modified in.
+	DatabaseRules DatabaseRules
+	// The naming conventions of each type of resource.
+	NamingRules map[string][]NamingRule
+	// The path of the file the query metrics are merged into, if any.
+	MetricsFile string
+	// Whether every resource operation reports the query metrics it consumed as a warning.
+	ReportOperationMetrics bool
+	// The provider of the tracer that operations and queries are traced with, if any.
+	TracerProvider *sdktrace.TracerProvider
+	// The log that queries modifying resources are recorded in, if any.
+	AuditLog *AuditLog
+	// Whether resources take over existing objects with the same name by default.
+	AdoptExisting bool
+}
+
+// Client is the provider's meta: the Fauna client along with the provider-wide settings shared by
+// every resource.
+type Client struct {
+	// The underlying client, which keeps track of the last seen transaction time.
+	*f.FaunaClient
+
+	config Config
+
+	// The description of the secret, once it has been probed.
+	key   *KeyInfo
+	keyMu sync.Mutex
+
+	// The query metrics consumed during the run.
+	metrics *metricsRecorder
+	tracer  trace.Tracer
+}
+
+func New(config Config) *Client {
+	if config.HTTP == nil {
+		config.HTTP = defaultHTTPClient(config.Endpoint)
+	}
+
+	if config.DefaultData == nil {
+		config.DefaultData = map[string]any{}
+	}
+
+	return &Client{
+		FaunaClient: f.NewFaunaClient(config.Secret, clientConfigs(config, config.Headers)...),
+		config:      config,
+		metrics:     newMetricsRecorder(config.MetricsFile),
+		tracer:      newTracer(config.TracerProvider),
+	}
+}
+
+// Database returns the slash-separated path of the database the secret is scoped to, relative to
+// the database the key was created in.
+func (client *Client) Database() string {
+	return client.config.Database
+}
+
+// DefaultData returns the metadata merged into the `data` of every resource.
+func (client *Client) DefaultData() map[string]any {
+	return client.config.DefaultData
+}
+
+// ReadOnly reports whether resources may only be read.
+func (client *Client) ReadOnly() bool {
+	return client.config.ReadOnly
+}
+
+// ReadOnlyPlanCheck reports whether plans with pending changes fail when the provider is read-only.
+func (client *Client) ReadOnlyPlanCheck() bool {
+	return client.config.ReadOnlyPlanCheck
+}
+
+// AdoptExisting reports whether resources take over existing objects with the same name by
+// default.
+func (client *Client) AdoptExisting() bool {
+	return client.config.AdoptExisting
+}
+
+// ReportOperationMetrics reports whether every resource operation reports the query metrics it
+// consumed as a warning.
+func (client *Client) ReportOperationMetrics() bool {
+	return client.config.ReportOperationMetrics
+}
+
+// CheckDatabase verifies that the database at the slash-separated path `database` may be modified.
+func (client *Client) CheckDatabase(database string) error {
+	return client.config.DatabaseRules.Check(database)
+}
+
+// Query sends `expr` to Fauna, tagged with the provider's query tags and with the resource
+// operation carried by `ctx`.
+func (client *Client) Query(ctx context.Context, expr f.Expr) (f.Value, error) {
+	tags := map[string]string{}
+	for key, value := range client.config.QueryTags {
+		tags[key] = value
+	}
+
+	if request, ok := RequestFromContext(ctx); ok {
+		for key, value := range request.Tags() {
+			tags[key] = value
+		}
+	}
+
+	headers := map[string]string{}
+	for key, value := range client.config.Headers {
+		headers[key] = value
+	}
+
+	if len(tags) != 0 {
+		headers[headerTags] = FormatTags(tags)
+	}
+
+	ctx, span := client.startQuery(ctx, headers)
+
+	// Query tags are specific to each query, but the underlying client only supports headers set
+	// for all of its queries, so a short-lived client sharing the HTTP client is created instead.
+	config := client.config
+	httpClient, recorder := recordResponses(config.HTTP)
+	config.HTTP = httpClient
+
+	conn := f.NewFaunaClient(config.Secret, clientConfigs(config, headers)...)
+	conn.SyncLastTxnTime(client.GetLastTxnTime())
+
+	start := time.Now()
+	res, err := conn.Query(expr)
+	client.logQuery(ctx, expr, recorder, time.Since(start), err)
+	client.recordMetrics(ctx, recorder)
+	client.audit(ctx, expr, res, err)
+	endQuery(span, recorder, err)
+
+	client.SyncLastTxnTime(conn.GetLastTxnTime())
+
+	return res, err
+}
+
+// recordMetrics attributes the query metrics of the response received by `recorder` to the
+// resource operation carried by `ctx`.
+func (client *Client) recordMetrics(ctx context.Context, recorder *responseRecorder) {
+	if recorder.status == 0 {
+		return
+	}
+
+	object := ProviderObject
+	if request, ok := RequestFromContext(ctx); ok {
+		object = request.Object()
+	}
+
+	client.metrics.record(object, metricsFromHeader(recorder.header))
+}
+
+// defaultHTTPClient mirrors the HTTP client the Fauna client creates when none is given: an HTTP/2
+// client, which speaks HTTP/2 over cleartext to plain-http endpoints. Queries are sent through
+// short-lived clients, so it is created once for their connections to be reused.
+func defaultHTTPClient(endpoint string) *http.Client {
+	transport := &http2.Transport{AllowHTTP: true}
+
+	if endpoint != "" && !strings.HasPrefix(endpoint, "https") {
+		transport.DialTLS = func(network string, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		}
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+func clientConfigs(config Config, headers map[string]string) []f.ClientConfig {
+	var configs []f.ClientConfig
+
+	if config.Endpoint != "" {
+		configs = append(configs, f.Endpoint(config.Endpoint))
+	}
+
+	if config.HTTP != nil {
+		configs = append(configs, f.HTTP(config.HTTP))
+	}
+
+	if config.QueryTimeout != 0 {
+		configs = append(configs, f.QueryTimeoutMS(uint64(config.QueryTimeout/time.Millisecond)))
+	}
+
+	if len(headers) != 0 {
+		// The underlying client adds its own headers to the map it is given.
+		copied := make(map[string]string, len(headers))
+		for key, value := range headers {
+			copied[key] = value
+		}
+
+		configs = append(configs, f.Headers(copied))
+	}
+
+	return configs
+}
@@ -0,0 +1,99 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+func TestClientQuery_headers(t *testing.T) {
+	var headers http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		w.Write([]byte(`{"resource": {"name": "users"}}`))
+	}))
+	defer server.Close()
+
+	conn := client.New(client.Config{
+		Secret:       "secret",
+		Endpoint:     server.URL,
+		HTTP:         server.Client(),
+		QueryTimeout: 5 * time.Second,
+		Headers:      map[string]string{"X-Team": "platform"},
+		QueryTags:    map[string]string{"pipeline_run": "1234"},
+	})
+
+	ctx := client.WithRequest(context.Background(), client.Request{
+		ResourceType: "fauna_collection",
+		ObjectName:   "users",
+		Operation:    client.OperationCreate,
+	})
+
+	if _, err := conn.Query(ctx, f.Get(f.Collection("users"))); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Authorization":   "Bearer secret",
+		"X-Team":          "platform",
+		"X-Query-Timeout": "5000",
+		"X-Fauna-Tags":    "pipeline_run=1234,tf_object_name=users,tf_operation=create,tf_resource_type=fauna_collection",
+	}
+	for key, value := range expected {
+		if headers.Get(key) != value {
+			t.Errorf("Expected header '%s' to be '%s', but got '%s'.", key, value, headers.Get(key))
+		}
+	}
+
+	// Tags are specific to each query.
+	if _, err := conn.Query(context.Background(), f.Get(f.Collection("users"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if tags := headers.Get("X-Fauna-Tags"); tags != "pipeline_run=1234" {
+		t.Errorf("Expected only the provider's query tags, but got '%s'.", tags)
+	}
+}
+
+func TestCheckTag(t *testing.T) {
+	if err := client.CheckTag("pipeline_run", "1234"); err != nil {
+		t.Errorf("Expected a valid tag, but got: %s", err)
+	}
+
+	for _, tag := range [][2]string{{"pipeline-run", "1234"}, {"run", "12 34"}, {"run", ""}} {
+		if err := client.CheckTag(tag[0], tag[1]); err == nil {
+			t.Errorf("Expected '%s=%s' to be an invalid tag.", tag[0], tag[1])
+		}
+	}
+}
+
+func TestClientQuery_defaultHTTP(t *testing.T) {
+	var proto string
+
+	// Like the Fauna client's own HTTP client, the default HTTP client speaks HTTP/2 over cleartext
+	// to plain-http endpoints, such as a local Fauna container.
+	server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto = r.Proto
+		w.Write([]byte(`{"resource": {"name": "users"}}`))
+	}), &http2.Server{}))
+	defer server.Close()
+
+	conn := client.New(client.Config{Secret: "secret", Endpoint: server.URL})
+
+	if _, err := conn.Query(context.Background(), f.Get(f.Collection("users"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if proto != "HTTP/2.0" {
+		t.Errorf("Expected the query to be sent over HTTP/2, but got %s.", proto)
+	}
+}
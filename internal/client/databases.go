@@ -0,0 +1,95 @@
+package client
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DatabaseRules restrict the databases that resources may be modified in.
+type DatabaseRules struct {
+	// Patterns of the database paths that may be modified. Empty means any.
+	Allowed []string
+	// Patterns of the database paths that may not be modified, taking precedence over `Allowed`.
+	Denied []string
+}
+
+// Check verifies that the database at the slash-separated path `database` may be modified. The
+// path is relative to the database the key was created in, which is itself the empty path.
+func (rules DatabaseRules) Check(database string) error {
+	database = strings.Trim(database, "/")
+
+	for _, pattern := range rules.Denied {
+		if MatchDatabasePattern(pattern, database) {
+			return fmt.Errorf("The database '%s' matches the pattern '%s' of `denied_databases`.", displayDatabase(database), pattern)
+		}
+	}
+
+	if len(rules.Allowed) == 0 {
+		return nil
+	}
+
+	for _, pattern := range rules.Allowed {
+		if MatchDatabasePattern(pattern, database) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("The database '%s' does not match any of the patterns of `allowed_databases`: %s.", displayDatabase(database), strings.Join(rules.Allowed, ", "))
+}
+
+// MatchDatabasePattern reports whether the database path `database` matches `pattern`, in which
+// `*`, `?` and `[...]` match within a single segment of the path as in `path.Match`, and a `**`
+// segment matches any number of segments. The pattern `/` matches the database the key was created
+// in.
+func MatchDatabasePattern(pattern string, database string) bool {
+	return matchSegments(split(pattern), split(database))
+}
+
+func matchSegments(pattern []string, database []string) bool {
+	if len(pattern) == 0 {
+		return len(database) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(database); i++ {
+			if matchSegments(pattern[1:], database[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(database) == 0 {
+		return false
+	}
+
+	if matched, err := path.Match(pattern[0], database[0]); err != nil || !matched {
+		return false
+	}
+
+	return matchSegments(pattern[1:], database[1:])
+}
+
+func split(database string) []string {
+	database = strings.Trim(database, "/")
+	if database == "" {
+		return nil
+	}
+
+	return strings.Split(database, "/")
+}
+
+// JoinDatabase returns the path of the child database `name` of the database at `parent`.
+func JoinDatabase(parent string, name string) string {
+	return strings.Trim(parent+"/"+name, "/")
+}
+
+func displayDatabase(database string) string {
+	if database == "" {
+		return "/"
+	}
+
+	return database
+}
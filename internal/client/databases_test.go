@@ -0,0 +1,54 @@
+package client_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+func TestMatchDatabasePattern(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		database string
+		matches  bool
+	}{
+		{"app/staging", "app/staging", true},
+		{"app/*", "app/staging", true},
+		{"app/*", "app/staging/eu", false},
+		{"app/**", "app/staging/eu", true},
+		{"app/**", "app", true},
+		{"**/prod", "app/eu/prod", true},
+		{"**", "", true},
+		{"/", "", true},
+		{"/", "app", false},
+		{"app_?", "app_1", true},
+		{"app", "application", false},
+	}
+
+	for _, c := range cases {
+		if matches := client.MatchDatabasePattern(c.pattern, c.database); matches != c.matches {
+			t.Errorf("Expected the pattern '%s' matching '%s' to be %t.", c.pattern, c.database, c.matches)
+		}
+	}
+}
+
+func TestDatabaseRules(t *testing.T) {
+	rules := client.DatabaseRules{Allowed: []string{"app/**"}, Denied: []string{"app/prod*"}}
+
+	if err := rules.Check("app/staging"); err != nil {
+		t.Errorf("Expected 'app/staging' to be allowed, but got: %s", err)
+	}
+
+	if err := rules.Check("app/production"); err == nil || !strings.Contains(err.Error(), "'app/prod*'") {
+		t.Errorf("Expected 'app/production' to be denied by 'app/prod*', but got: %v", err)
+	}
+
+	if err := rules.Check(""); err == nil || !strings.Contains(err.Error(), "allowed_databases") {
+		t.Errorf("Expected the root database not to be allowed, but got: %v", err)
+	}
+
+	if err := (client.DatabaseRules{}).Check("anything"); err != nil {
+		t.Errorf("Expected any database to be allowed without rules, but got: %s", err)
+	}
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// The tflog subsystem queries are logged in. Its level can be set separately from the rest of the
+// provider's logs with the `TF_LOG_PROVIDER_FAUNA_QUERY` environment variable.
+const LogSubsystem = "query"
+
+// The response headers carrying Fauna's query metrics.
+var MetricHeaders = []string{"x-compute-ops", "x-byte-read-ops", "x-byte-write-ops"}
+
+// The keys of FQL objects whose values are masked in logs.
+var maskedKeys = []string{"secret", "password"}
+
+const masked = "***"
+
+// responseRecorder is an HTTP transport that remembers the last response it received, so that its
+// status and headers can be logged whether or not the query succeeded.
+type responseRecorder struct {
+	transport http.RoundTripper
+	status    int
+	header    http.Header
+}
+
+func (recorder *responseRecorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := recorder.transport.RoundTrip(request)
+	if response != nil {
+		recorder.status, recorder.header = response.StatusCode, response.Header
+	}
+
+	return response, err
+}
+
+// recordResponses returns a copy of `httpClient` whose responses are remembered by the returned
+// recorder. The copy shares the transport, and so the connections, of `httpClient`.
+func recordResponses(httpClient *http.Client) (*http.Client, *responseRecorder) {
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	recorder := &responseRecorder{transport: transport}
+
+	copied := *httpClient
+	copied.Transport = recorder
+
+	return &copied, recorder
+}
+
+type queryLoggerKey struct{}
+
+// WithQueryLogger returns a copy of `ctx` carrying the tflog subsystem the queries of `client` are
+// logged in, so that the queries of an operation share it rather than each creating it.
+func (client *Client) WithQueryLogger(ctx context.Context) context.Context {
+	if ctx.Value(queryLoggerKey{}) == client {
+		return ctx
+	}
+
+	ctx = tflog.NewSubsystem(ctx, LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_FAUNA_QUERY"))
+	ctx = tflog.SubsystemMaskAllFieldValuesStrings(ctx, LogSubsystem, client.config.Secret)
+
+	return context.WithValue(ctx, queryLoggerKey{}, client)
+}
+
+// logQuery logs a query sent on behalf of the resource operation carried by `ctx`. Queries issued
+// outside of an operation create the tflog subsystem themselves.
+func (client *Client) logQuery(ctx context.Context, expr f.Expr, recorder *responseRecorder, latency time.Duration, err error) {
+	ctx = client.WithQueryLogger(ctx)
+
+	fields := map[string]any{
+		"fql":        MaskExpr(expr),
+		"latency_ms": latency.Milliseconds(),
+	}
+
+	if request, ok := RequestFromContext(ctx); ok {
+		fields["tf_resource_type"] = request.ResourceType
+		fields["tf_object_name"] = request.ObjectName
+		fields["tf_operation"] = string(request.Operation)
+	}
+
+	if recorder.status != 0 {
+		fields["status"] = recorder.status
+	}
+
+	for _, header := range MetricHeaders {
+		if value := recorder.header.Get(header); value != "" {
+			fields[strings.ReplaceAll(strings.TrimPrefix(header, "x-"), "-", "_")] = value
+		}
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, LogSubsystem, "Fauna query failed", fields)
+		return
+	}
+
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Fauna query succeeded", fields)
+}
+
+// MaskExpr renders `expr` as the JSON sent to Fauna, with the values of secret-bearing keys, such
+// as the passwords of credentials, masked.
+func MaskExpr(expr f.Expr) string {
+	rendered, err := json.Marshal(expr)
+	if err != nil {
+		return "<unrenderable expression>"
+	}
+
+	var decoded any
+	if err := json.Unmarshal(rendered, &decoded); err != nil {
+		return string(rendered)
+	}
+
+	masked, err := json.Marshal(maskValues(decoded))
+	if err != nil {
+		return string(rendered)
+	}
+
+	return string(masked)
+}
+
+func maskValues(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, child := range value {
+			if containsFold(maskedKeys, key) {
+				value[key] = masked
+			} else {
+				value[key] = maskValues(child)
+			}
+		}
+	case []any:
+		for i, child := range value {
+			value[i] = maskValues(child)
+		}
+	}
+
+	return value
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+func TestClientQuery_logs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Compute-Ops", "2")
+		w.Header().Set("X-Byte-Read-Ops", "3")
+		w.Header().Set("X-Byte-Write-Ops", "1")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors": [{"code": "instance already exists", "description": "Collection already exists."}]}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	ctx = client.WithRequest(ctx, client.Request{
+		ResourceType: "fauna_collection",
+		ObjectName:   "users",
+		Operation:    client.OperationCreate,
+	})
+
+	conn := client.New(client.Config{Secret: "fnSECRET", Endpoint: server.URL, HTTP: server.Client()})
+
+	if _, err := conn.Query(ctx, f.CreateCollection(f.Obj{"name": "users", "data": f.Obj{"key": "fnSECRET"}})); err == nil {
+		t.Fatal("Expected the query to fail.")
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("Expected a single log entry, but got %d.", len(entries))
+	}
+
+	expected := map[string]any{
+		"@module":          "provider.query",
+		"tf_resource_type": "fauna_collection",
+		"tf_object_name":   "users",
+		"tf_operation":     "create",
+		"status":           float64(http.StatusBadRequest),
+		"compute_ops":      "2",
+		"byte_read_ops":    "3",
+		"byte_write_ops":   "1",
+	}
+	for key, value := range expected {
+		if entries[0][key] != value {
+			t.Errorf("Expected the log field '%s' to be %v, but got %v.", key, value, entries[0][key])
+		}
+	}
+
+	if fql, _ := entries[0]["fql"].(string); !strings.Contains(fql, `"create_collection"`) || strings.Contains(fql, "fnSECRET") {
+		t.Errorf("Expected the logged FQL to be rendered with the secret masked, but got: %s", fql)
+	}
+}
+
+func TestClientWithQueryLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"resource": {"name": "users"}}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	conn := client.New(client.Config{Secret: "fnSECRET", Endpoint: server.URL, HTTP: server.Client()})
+
+	ctx := conn.WithQueryLogger(tflogtest.RootLogger(context.Background(), &output))
+	if again := conn.WithQueryLogger(ctx); again != ctx {
+		t.Error("Expected the query logger of the context to be reused.")
+	}
+
+	// The logger of another client masks its own secret.
+	other := client.New(client.Config{Secret: "fnOTHER", Endpoint: server.URL, HTTP: server.Client()})
+	if other.WithQueryLogger(ctx) == ctx {
+		t.Error("Expected another client to create its own query logger.")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := conn.Query(ctx, f.Get(f.Collection("fnSECRET"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 log entries, but got %d.", len(entries))
+	}
+
+	for _, entry := range entries {
+		if entry["@module"] != "provider.query" {
+			t.Errorf("Expected the query to be logged in the query subsystem, but got '%v'.", entry["@module"])
+		}
+
+		if fql, _ := entry["fql"].(string); strings.Contains(fql, "fnSECRET") {
+			t.Errorf("Expected the secret to be masked, but got: %s", fql)
+		}
+	}
+}
+
+func TestMaskExpr(t *testing.T) {
+	rendered := client.MaskExpr(f.Login(f.Ref("classes/users/1"), f.Obj{"password": "hunter2"}))
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(rendered), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(rendered, "hunter2") || !strings.Contains(rendered, `"password":"***"`) {
+		t.Errorf("Expected the password to be masked, but got: %s", rendered)
+	}
+}
@@ -0,0 +1,249 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The object that queries issued outside of any resource operation, such as verifying the secret,
+// are attributed to.
+const ProviderObject = "provider"
+
+// QueryMetrics totals the query metrics reported by Fauna.
+type QueryMetrics struct {
+	Queries      int64 `json:"queries"`
+	ComputeOps   int64 `json:"compute_ops"`
+	ByteReadOps  int64 `json:"byte_read_ops"`
+	ByteWriteOps int64 `json:"byte_write_ops"`
+}
+
+// Add adds `other` to the metrics.
+func (metrics *QueryMetrics) Add(other QueryMetrics) {
+	metrics.Queries += other.Queries
+	metrics.ComputeOps += other.ComputeOps
+	metrics.ByteReadOps += other.ByteReadOps
+	metrics.ByteWriteOps += other.ByteWriteOps
+}
+
+// Sub returns the metrics consumed since `earlier`.
+func (metrics QueryMetrics) Sub(earlier QueryMetrics) QueryMetrics {
+	return QueryMetrics{
+		Queries:      metrics.Queries - earlier.Queries,
+		ComputeOps:   metrics.ComputeOps - earlier.ComputeOps,
+		ByteReadOps:  metrics.ByteReadOps - earlier.ByteReadOps,
+		ByteWriteOps: metrics.ByteWriteOps - earlier.ByteWriteOps,
+	}
+}
+
+func (metrics QueryMetrics) String() string {
+	return fmt.Sprintf("%d compute ops, %d byte read ops and %d byte write ops over %d queries", metrics.ComputeOps, metrics.ByteReadOps, metrics.ByteWriteOps, metrics.Queries)
+}
+
+// metricsFromHeader reads the query metrics of a single query from the headers of its response.
+func metricsFromHeader(header http.Header) QueryMetrics {
+	parse := func(name string) int64 {
+		value, _ := strconv.ParseInt(header.Get(name), 10, 64)
+		return value
+	}
+
+	return QueryMetrics{
+		Queries:      1,
+		ComputeOps:   parse("x-compute-ops"),
+		ByteReadOps:  parse("x-byte-read-ops"),
+		ByteWriteOps: parse("x-byte-write-ops"),
+	}
+}
+
+// How long writing the metrics file waits for another plugin process to finish writing it, and
+// after how long a lock left behind by an interrupted process is broken.
+const (
+	metricsLockTimeout = 5 * time.Second
+	metricsLockStale   = 30 * time.Second
+)
+
+// MetricsReport is the content of the metrics file.
+type MetricsReport struct {
+	// The metrics of every plugin process that wrote to the file.
+	Total QueryMetrics `json:"total"`
+	// The metrics of each Fauna object, identified as by Request.Object, across plugin processes.
+	Objects map[string]QueryMetrics `json:"objects"`
+	// The metrics of each plugin process that wrote to the file, identified by the time the
+	// provider was configured, the process ID and the number of the configuration within the
+	// process. Terraform runs a plugin process for the plan and another for the apply, and one for
+	// each provider configuration.
+	Processes map[string]ProcessMetrics `json:"processes"`
+}
+
+// ProcessMetrics is the part of the metrics file written by a single plugin process, or provider
+// configuration within it.
+type ProcessMetrics struct {
+	Total   QueryMetrics            `json:"total"`
+	Objects map[string]QueryMetrics `json:"objects"`
+}
+
+// metricsRecorder aggregates the query metrics of a plugin process per Fauna object.
+type metricsRecorder struct {
+	mu      sync.Mutex
+	objects map[string]QueryMetrics
+	total   QueryMetrics
+	// The path of the file the report is written to, if any.
+	path string
+	// The key of the metrics of this process in the file.
+	process string
+}
+
+// The number of metrics recorders created by this process.
+var metricsRecorders int64
+
+func newMetricsRecorder(path string) *metricsRecorder {
+	return &metricsRecorder{
+		objects: map[string]QueryMetrics{},
+		path:    path,
+		process: fmt.Sprintf("%s-%d-%d", time.Now().UTC().Format("20060102T150405Z"), os.Getpid(), atomic.AddInt64(&metricsRecorders, 1)),
+	}
+}
+
+func (recorder *metricsRecorder) record(object string, metrics QueryMetrics) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	objectMetrics := recorder.objects[object]
+	objectMetrics.Add(metrics)
+	recorder.objects[object] = objectMetrics
+	recorder.total.Add(metrics)
+}
+
+// write merges the metrics of this process into the metrics file, alongside those of the other
+// plugin processes that wrote to it. The file is locked while it is merged, and replaced
+// atomically, so that it always holds a complete report, even if the run is interrupted.
+func (recorder *metricsRecorder) write() error {
+	unlock, err := lockFile(recorder.path+".lock", metricsLockTimeout, metricsLockStale)
+	if err != nil {
+		return fmt.Errorf("Failed to write the metrics file '%s': %s", recorder.path, err)
+	}
+	defer unlock()
+
+	var report MetricsReport
+	if contents, err := os.ReadFile(recorder.path); err == nil {
+		if err := json.Unmarshal(contents, &report); err != nil {
+			return fmt.Errorf("Failed to read the metrics file '%s', which is not a metrics report: %s", recorder.path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to read the metrics file '%s': %s", recorder.path, err)
+	}
+
+	if report.Processes == nil {
+		report.Processes = map[string]ProcessMetrics{}
+	}
+
+	objects := make(map[string]QueryMetrics, len(recorder.objects))
+	for object, metrics := range recorder.objects {
+		objects[object] = metrics
+	}
+
+	report.Processes[recorder.process] = ProcessMetrics{Total: recorder.total, Objects: objects}
+
+	report.Total, report.Objects = QueryMetrics{}, map[string]QueryMetrics{}
+	for _, process := range report.Processes {
+		report.Total.Add(process.Total)
+
+		for object, metrics := range process.Objects {
+			objectMetrics := report.Objects[object]
+			objectMetrics.Add(metrics)
+			report.Objects[object] = objectMetrics
+		}
+	}
+
+	contents, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	temporary, err := os.CreateTemp(filepath.Dir(recorder.path), filepath.Base(recorder.path)+".*")
+	if err != nil {
+		return fmt.Errorf("Failed to write the metrics file '%s': %s", recorder.path, err)
+	}
+	defer os.Remove(temporary.Name())
+
+	if _, err := temporary.Write(append(contents, '\n')); err != nil {
+		temporary.Close()
+		return fmt.Errorf("Failed to write the metrics file '%s': %s", recorder.path, err)
+	}
+
+	if err := temporary.Close(); err != nil {
+		return fmt.Errorf("Failed to write the metrics file '%s': %s", recorder.path, err)
+	}
+
+	if err := os.Rename(temporary.Name(), recorder.path); err != nil {
+		return fmt.Errorf("Failed to write the metrics file '%s': %s", recorder.path, err)
+	}
+
+	return nil
+}
+
+// lockFile creates the lock file `path`, waiting up to `timeout` for another process to remove it.
+// A lock file older than `stale` is left behind by an interrupted process, and is broken. The
+// returned function removes the lock file.
+func lockFile(path string, timeout time.Duration, stale time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > stale {
+			os.Remove(path)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("the lock file '%s' is held by another process", path)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// WriteMetrics merges the query metrics consumed so far by this plugin process into the metrics
+// file, if any. It is written after every resource operation rather than after every query.
+func (client *Client) WriteMetrics() error {
+	client.metrics.mu.Lock()
+	defer client.metrics.mu.Unlock()
+
+	if client.metrics.path == "" {
+		return nil
+	}
+
+	return client.metrics.write()
+}
+
+// Metrics returns the query metrics consumed by the Fauna object `object`, identified as by
+// Request.Object, since the provider was configured.
+func (client *Client) Metrics(object string) QueryMetrics {
+	client.metrics.mu.Lock()
+	defer client.metrics.mu.Unlock()
+
+	return client.metrics.objects[object]
+}
+
+// TotalMetrics returns the query metrics consumed by every resource since the provider was
+// configured.
+func (client *Client) TotalMetrics() QueryMetrics {
+	client.metrics.mu.Lock()
+	defer client.metrics.mu.Unlock()
+
+	return client.metrics.total
+}
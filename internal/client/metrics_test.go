@@ -0,0 +1,106 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+func TestClientQuery_metrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Compute-Ops", "2")
+		w.Header().Set("X-Byte-Read-Ops", "5")
+		w.Header().Set("X-Byte-Write-Ops", "1")
+		w.Write([]byte(`{"resource": {"name": "users"}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "metrics.json")
+	conn := client.New(client.Config{Secret: "secret", Endpoint: server.URL, HTTP: server.Client(), MetricsFile: path})
+
+	ctx := client.WithRequest(context.Background(), client.Request{
+		ResourceType: "fauna_collection",
+		ObjectName:   "users",
+		Operation:    client.OperationCreate,
+	})
+
+	for _, ctx := range []context.Context{ctx, ctx, context.Background()} {
+		if _, err := conn.Query(ctx, f.Get(f.Collection("users"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := client.QueryMetrics{Queries: 2, ComputeOps: 4, ByteReadOps: 10, ByteWriteOps: 2}
+	if metrics := conn.Metrics("fauna_collection.users"); metrics != expected {
+		t.Errorf("Expected the metrics of the collection to be %+v, but got %+v.", expected, metrics)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the metrics file to only be written once requested, but got: %v", err)
+	}
+
+	if err := conn.WriteMetrics(); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var report client.MetricsReport
+	if err := json.Unmarshal(contents, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Total.Queries != 3 || report.Total.ComputeOps != 6 {
+		t.Errorf("Expected the total to cover every query, but got %+v.", report.Total)
+	}
+
+	if report.Objects[client.ProviderObject].Queries != 1 {
+		t.Errorf("Expected the query without a resource to be attributed to the provider, but got %+v.", report.Objects)
+	}
+
+	// Another plugin process, such as that of the apply after the plan, adds to the file rather
+	// than replacing it.
+	other := client.New(client.Config{Secret: "secret", Endpoint: server.URL, HTTP: server.Client(), MetricsFile: path})
+	if _, err := other.Query(ctx, f.Get(f.Collection("users"))); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, conn := range []*client.Client{other, conn} {
+		if err := conn.WriteMetrics(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	contents, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report = client.MetricsReport{}
+	if err := json.Unmarshal(contents, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Processes) != 2 {
+		t.Errorf("Expected the metrics of 2 processes, but got %d.", len(report.Processes))
+	}
+
+	if report.Total.Queries != 4 || report.Objects["fauna_collection.users"].Queries != 3 {
+		t.Errorf("Expected the report to cover the queries of both processes, but got %+v.", report)
+	}
+
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("Expected the lock file to be removed, but got: %v", err)
+	}
+}
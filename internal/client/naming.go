@@ -0,0 +1,46 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NamingRule is a convention that the names of a type of resource must follow.
+type NamingRule struct {
+	// The pattern names must match, if any.
+	Pattern *regexp.Regexp
+	// The prefix names must start with, if any.
+	Prefix string
+	// The suffix names must end with, if any.
+	Suffix string
+}
+
+// Check verifies that `name` follows the rule.
+func (rule NamingRule) Check(name string) error {
+	if rule.Prefix != "" && !strings.HasPrefix(name, rule.Prefix) {
+		return fmt.Errorf("'%s' does not start with the prefix '%s'.", name, rule.Prefix)
+	}
+
+	if rule.Suffix != "" && !strings.HasSuffix(name, rule.Suffix) {
+		return fmt.Errorf("'%s' does not end with the suffix '%s'.", name, rule.Suffix)
+	}
+
+	if rule.Pattern != nil && !rule.Pattern.MatchString(name) {
+		return fmt.Errorf("'%s' does not match the pattern '%s'.", name, rule.Pattern)
+	}
+
+	return nil
+}
+
+// CheckNamingRules verifies that `name` follows every naming rule configured for resources of type
+// `resourceType`.
+func (client *Client) CheckNamingRules(name string, resourceType string) error {
+	for _, rule := range client.config.NamingRules[resourceType] {
+		if err := rule.Check(name); err != nil {
+			return fmt.Errorf("The name of a %s does not follow the naming rules of the provider: %s", resourceType, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package client_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+func TestNamingRule(t *testing.T) {
+	rule := client.NamingRule{Pattern: regexp.MustCompile(`^[a-z_]+$`), Prefix: "idx_", Suffix: "_by_id"}
+
+	cases := []struct {
+		name    string
+		message string
+	}{
+		{"idx_users_by_id", ""},
+		{"users_by_id", "prefix 'idx_'"},
+		{"idx_users", "suffix '_by_id'"},
+		{"idx_Users_by_id", "pattern '^[a-z_]+$'"},
+	}
+
+	for _, c := range cases {
+		err := rule.Check(c.name)
+
+		switch {
+		case c.message == "" && err != nil:
+			t.Errorf("Expected '%s' to follow the rule, but got: %s", c.name, err)
+		case c.message != "" && (err == nil || !strings.Contains(err.Error(), c.message)):
+			t.Errorf("Expected '%s' to break the rule with an error mentioning %s, but got: %v", c.name, c.message, err)
+		}
+	}
+}
+
+func TestCheckNamingRules(t *testing.T) {
+	conn := client.New(client.Config{NamingRules: map[string][]client.NamingRule{
+		"fauna_index": {{Prefix: "idx_"}, {Suffix: "_v1"}},
+	}})
+
+	if err := conn.CheckNamingRules("idx_users_v1", "fauna_index"); err != nil {
+		t.Errorf("Expected 'idx_users_v1' to follow every rule, but got: %s", err)
+	}
+
+	if err := conn.CheckNamingRules("idx_users", "fauna_index"); err == nil || !strings.Contains(err.Error(), "fauna_index") {
+		t.Errorf("Expected 'idx_users' to break the second rule, but got: %v", err)
+	}
+
+	if err := conn.CheckNamingRules("users", "fauna_collection"); err != nil {
+		t.Errorf("Expected collections to have no naming rules, but got: %s", err)
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+)
+
+// The endpoint queries are sent to when none is configured.
+const DefaultEndpoint = "https://db.fauna.com"
+
+// Roles determined by Probe.
+const (
+	RoleAdmin  = "admin"
+	RoleServer = "server"
+)
+
+// KeyInfo describes the secret used by a Client.
+type KeyInfo struct {
+	// The role of the secret: `admin`, `server`, or the role it was scoped with.
+	Role string
+	// The slash-separated path of the database the secret is scoped to, empty for the database
+	// that the key was created in.
+	Database string
+	// The URL of the endpoint queries are sent to.
+	Endpoint string
+}
+
+var ErrSchemaAccessDenied = errors.New("The secret is not allowed to read the schema of the database.")
+
+// Probe verifies that the secret is valid and allowed to manage schema, issuing the cheapest
+// queries that tell an admin secret apart from a server one.
+func (client *Client) Probe(ctx context.Context) (KeyInfo, error) {
+	info := KeyInfo{Database: client.config.Database, Endpoint: client.Endpoint()}
+
+	if _, err := client.Query(ctx, f.Paginate(f.Collections(), f.Size(1))); err != nil {
+		if errors.As(err, &f.PermissionDeniedError{}) {
+			return info, ErrSchemaAccessDenied
+		}
+
+		return info, err
+	}
+
+	_, err := client.Query(ctx, f.Paginate(f.Databases(), f.Size(1)))
+	switch {
+	case err == nil:
+		info.Role = RoleAdmin
+	case errors.As(err, &f.PermissionDeniedError{}):
+		info.Role = RoleServer
+	default:
+		return info, err
+	}
+
+	// A secret scoped to a custom role passes as a server one, but its role is known regardless.
+	if role := client.config.Role; role != "" && info.Role != RoleAdmin {
+		info.Role = strings.TrimPrefix(role, "@role/")
+	}
+
+	client.keyMu.Lock()
+	client.key = &info
+	client.keyMu.Unlock()
+
+	return info, nil
+}
+
+// Key describes the secret, probing it if it has not been already.
+func (client *Client) Key(ctx context.Context) (KeyInfo, error) {
+	client.keyMu.Lock()
+	key := client.key
+	client.keyMu.Unlock()
+
+	if key != nil {
+		return *key, nil
+	}
+
+	return client.Probe(ctx)
+}
+
+// Endpoint returns the URL of the endpoint queries are sent to.
+func (client *Client) Endpoint() string {
+	if client.config.Endpoint == "" {
+		return DefaultEndpoint
+	}
+
+	return client.config.Endpoint
+}
@@ -0,0 +1,84 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+const permissionDenied = `{"errors": [{"code": "permission denied", "description": "Insufficient privileges to perform the action."}]}`
+
+// newProbeServer serves a Fauna endpoint that allows reading collections and databases as given.
+func newProbeServer(t *testing.T, collections bool, databases bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		allowed := collections
+		if strings.Contains(string(body), `"databases"`) {
+			allowed = databases
+		}
+
+		if !allowed {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(permissionDenied))
+			return
+		}
+
+		w.Write([]byte(`{"resource": {"data": []}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestProbe(t *testing.T) {
+	cases := []struct {
+		collections bool
+		databases   bool
+		role        string
+		err         error
+	}{
+		{true, true, client.RoleAdmin, nil},
+		{true, false, client.RoleServer, nil},
+		{false, false, "", client.ErrSchemaAccessDenied},
+	}
+
+	for _, c := range cases {
+		server := newProbeServer(t, c.collections, c.databases)
+		conn := client.New(client.Config{Secret: "secret", Endpoint: server.URL, HTTP: server.Client(), Database: "app"})
+
+		key, err := conn.Probe(context.Background())
+		if !errors.Is(err, c.err) {
+			t.Fatalf("Expected error '%v', but got '%v'.", c.err, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		if key.Role != c.role || key.Database != "app" || key.Endpoint != server.URL {
+			t.Errorf("Unexpected key description: %+v", key)
+		}
+	}
+}
+
+func TestProbe_unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errors": [{"code": "unauthorized", "description": "Unauthorized"}]}`))
+	}))
+	defer server.Close()
+
+	conn := client.New(client.Config{Secret: "secret", Endpoint: server.URL, HTTP: server.Client()})
+
+	if _, err := conn.Probe(context.Background()); !errors.As(err, &f.Unauthorized{}) {
+		t.Errorf("Expected an unauthorised error, but got '%v'.", err)
+	}
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Operation is the CRUD operation of a resource that queries are issued for.
+type Operation string
+
+const (
+	OperationCreate Operation = "create"
+	OperationRead   Operation = "read"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+)
+
+// Request describes the resource operation that queries are issued for.
+type Request struct {
+	// The Terraform type of the resource, such as `fauna_collection`.
+	ResourceType string
+	// The name of the Fauna object managed by the resource.
+	ObjectName string
+	Operation  Operation
+}
+
+// Object identifies the Fauna object managed by the resource as `<type>.<name>`, such as
+// `fauna_collection.users`. This is not the address of the resource: the Terraform plugin protocol
+// does not expose it to providers, so resources with `count` or `for_each`, or managing objects of
+// the same name through providers scoped to different databases, cannot be told apart by it.
+func (request Request) Object() string {
+	return fmt.Sprintf("%s.%s", request.ResourceType, request.ObjectName)
+}
+
+// The number of query tags identifying a request.
+const AutomaticTags = 3
+
+// Tags returns the query tags identifying the request.
+func (request Request) Tags() map[string]string {
+	tags := map[string]string{}
+
+	if request.ResourceType != "" {
+		tags["tf_resource_type"] = SanitiseTagValue(request.ResourceType)
+	}
+
+	if request.ObjectName != "" {
+		tags["tf_object_name"] = SanitiseTagValue(request.ObjectName)
+	}
+
+	if request.Operation != "" {
+		tags["tf_operation"] = SanitiseTagValue(string(request.Operation))
+	}
+
+	return tags
+}
+
+type requestKey struct{}
+
+// WithRequest returns a context carrying `request`, which queries issued with it are attributed to.
+func WithRequest(ctx context.Context, request Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+// RequestFromContext returns the request carried by `ctx`, if any.
+func RequestFromContext(ctx context.Context) (Request, bool) {
+	request, ok := ctx.Value(requestKey{}).(Request)
+	return request, ok
+}
+
+// Fauna's limits on query tags.
+const (
+	MaximumTags           = 25
+	MaximumTagKeyLength   = 40
+	MaximumTagValueLength = 80
+)
+
+var tagPattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+var invalidTagCharacters = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// CheckTag verifies that a query tag satisfies Fauna's rules.
+func CheckTag(key string, value string) error {
+	if !tagPattern.MatchString(key) || len(key) > MaximumTagKeyLength {
+		return fmt.Errorf("The query tag key '%s' must be made up of 1 to %d letters, digits and underscores.", key, MaximumTagKeyLength)
+	}
+
+	if !tagPattern.MatchString(value) || len(value) > MaximumTagValueLength {
+		return fmt.Errorf("The value '%s' of the query tag '%s' must be made up of 1 to %d letters, digits and underscores.", value, key, MaximumTagValueLength)
+	}
+
+	return nil
+}
+
+// SanitiseTagValue turns `value` into a valid query tag value.
+func SanitiseTagValue(value string) string {
+	value = invalidTagCharacters.ReplaceAllString(value, "_")
+	if len(value) > MaximumTagValueLength {
+		value = value[:MaximumTagValueLength]
+	}
+
+	return value
+}
+
+// FormatTags formats query tags as the value of the `x-fauna-tags` header.
+func FormatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=%s", key, tags[key])
+	}
+
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The name of the tracer spans are created with.
+const tracerName = "github.com/wordcollector/terraform-provider-fauna"
+
+// The header carrying the trace context of a query to Fauna.
+const headerTraceParent = "traceparent"
+
+func newTracer(provider *sdktrace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		return trace.NewNoopTracerProvider().Tracer(tracerName)
+	}
+
+	return provider.Tracer(tracerName)
+}
+
+// StartOperation attributes the queries issued with the returned context to `request`, within a
+// span covering the whole operation, and logs them in a tflog subsystem they share. The span must be ended with EndOperation.
+func (client *Client) StartOperation(ctx context.Context, request Request) (context.Context, trace.Span) {
+	ctx = client.WithQueryLogger(WithRequest(ctx, request))
+
+	return client.tracer.Start(ctx, fmt.Sprintf("%s %s", request.ResourceType, request.Operation), trace.WithAttributes(client.requestAttributes(request)...))
+}
+
+// EndOperation ends the span of an operation that consumed `metrics` and failed with `err`, if it
+// did.
+func (client *Client) EndOperation(ctx context.Context, span trace.Span, metrics QueryMetrics, err error) {
+	span.SetAttributes(metricsAttributes(metrics)...)
+	span.SetAttributes(attribute.Int64("fauna.queries", metrics.Queries))
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// startQuery starts the span of a query, and adds the trace context to its `headers`.
+func (client *Client) startQuery(ctx context.Context, headers map[string]string) (context.Context, trace.Span) {
+	var attributes []attribute.KeyValue
+	if request, ok := RequestFromContext(ctx); ok {
+		attributes = client.requestAttributes(request)
+	}
+
+	ctx, span := client.tracer.Start(ctx, "fauna.query", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	if traceParent := carrier.Get(headerTraceParent); traceParent != "" {
+		headers[headerTraceParent] = traceParent
+	}
+
+	return ctx, span
+}
+
+// endQuery ends the span of a query whose response was received by `recorder`.
+func endQuery(span trace.Span, recorder *responseRecorder, err error) {
+	if recorder.status != 0 {
+		span.SetAttributes(semconv.HTTPStatusCode(recorder.status))
+		span.SetAttributes(metricsAttributes(metricsFromHeader(recorder.header))...)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+func (client *Client) requestAttributes(request Request) []attribute.KeyValue {
+	database := client.Database()
+	if database == "" {
+		database = "/"
+	}
+
+	return []attribute.KeyValue{
+		attribute.String("tf.resource_type", request.ResourceType),
+		attribute.String("fauna.object_name", request.ObjectName),
+		attribute.String("tf.operation", string(request.Operation)),
+		attribute.String("fauna.database", database),
+	}
+}
+
+func metricsAttributes(metrics QueryMetrics) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.Int64("fauna.compute_ops", metrics.ComputeOps),
+		attribute.Int64("fauna.byte_read_ops", metrics.ByteReadOps),
+		attribute.Int64("fauna.byte_write_ops", metrics.ByteWriteOps),
+	}
+}
@@ -0,0 +1,74 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+func TestClientQuery_tracing(t *testing.T) {
+	var traceParent string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+		w.Header().Set("X-Compute-Ops", "3")
+		w.Write([]byte(`{"resource": {"name": "users"}}`))
+	}))
+	defer server.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	conn := client.New(client.Config{Secret: "secret", Endpoint: server.URL, HTTP: server.Client(), Database: "app", TracerProvider: provider})
+
+	request := client.Request{ResourceType: "fauna_collection", ObjectName: "users", Operation: client.OperationCreate}
+	ctx, span := conn.StartOperation(context.Background(), request)
+
+	if _, err := conn.Query(ctx, f.Get(f.Collection("users"))); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.EndOperation(ctx, span, conn.Metrics(request.Object()), nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected a span for the operation and one for the query, but got %d.", len(spans))
+	}
+
+	query, operation := spans[0], spans[1]
+
+	if operation.Name != "fauna_collection create" || query.Parent.SpanID() != operation.SpanContext.SpanID() {
+		t.Errorf("Expected the query span to be a child of the operation span, but got '%s' with parent %s.", operation.Name, query.Parent.SpanID())
+	}
+
+	if !strings.Contains(traceParent, query.SpanContext.TraceID().String()) || !strings.Contains(traceParent, query.SpanContext.SpanID().String()) {
+		t.Errorf("Expected the traceparent header to carry the query span, but got '%s'.", traceParent)
+	}
+
+	for _, stub := range spans {
+		attributes := map[string]string{}
+		for _, attribute := range stub.Attributes {
+			attributes[string(attribute.Key)] = attribute.Value.Emit()
+		}
+
+		expected := map[string]string{
+			"tf.resource_type":  "fauna_collection",
+			"fauna.object_name": "users",
+			"fauna.database":    "app",
+			"fauna.compute_ops": "3",
+		}
+		for key, value := range expected {
+			if attributes[key] != value {
+				t.Errorf("Expected the attribute '%s' of the span '%s' to be '%s', but got '%s'.", key, stub.Name, value, attributes[key])
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// The endpoints of each Fauna region group.
+var RegionGroupEndpoints = map[string]string{
+	"classic": "https://db.fauna.com",
+	"us":      "https://db.us.fauna.com",
+	"eu":      "https://db.eu.fauna.com",
+	"preview": "https://db.fauna-preview.com",
+	"local":   "http://localhost:8443",
+}
+
+// The region groups in the order in which they are documented.
+var RegionGroups = []string{"classic", "us", "eu", "preview", "local"}
+
+// ResolveEndpoint determines the endpoint to send queries to, giving precedence to an explicit
+// `endpoint` over a `region_group`. An empty string means the client default is to be used.
+func ResolveEndpoint(endpoint string, regionGroup string) (string, error) {
+	if endpoint != "" {
+		return endpoint, nil
+	}
+
+	if regionGroup == "" {
+		return "", nil
+	}
+
+	if endpoint, ok := RegionGroupEndpoints[regionGroup]; ok {
+		return endpoint, nil
+	}
+
+	return "", fmt.Errorf("Unknown region group '%s'.", regionGroup)
+}
+
+// CheckEndpoint verifies that `endpoint` is a well-formed URL with an HTTP(S) scheme, a host and,
+// if one is given, a valid port.
+func CheckEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("The endpoint '%s' is not a valid URL: %s", endpoint, err)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("The endpoint '%s' must use the 'http' or 'https' scheme.", endpoint)
+	}
+
+	if parsed.Hostname() == "" {
+		return fmt.Errorf("The endpoint '%s' must specify a host.", endpoint)
+	}
+
+	if port := parsed.Port(); port != "" {
+		if number, err := strconv.Atoi(port); err != nil || number < 1 || number > 65535 {
+			return fmt.Errorf("The endpoint '%s' specifies an invalid port '%s'.", endpoint, port)
+		}
+	} else if parsed.Host != parsed.Hostname() {
+		return fmt.Errorf("The endpoint '%s' specifies an empty port.", endpoint)
+	}
+
+	return nil
+}
+
+func validateEndpoint(value any, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if err := CheckEndpoint(value.(string)); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid endpoint",
+			Detail:        err.Error(),
+			AttributePath: path,
+		})
+	}
+
+	return diags
+}
@@ -0,0 +1,51 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider"
+)
+
+func TestResolveEndpoint(t *testing.T) {
+	cases := []struct {
+		endpoint    string
+		regionGroup string
+		expected    string
+	}{
+		{"", "", ""},
+		{"", "us", "https://db.us.fauna.com"},
+		{"", "eu", "https://db.eu.fauna.com"},
+		{"", "local", "http://localhost:8443"},
+		{"https://fauna.example.com:8443", "eu", "https://fauna.example.com:8443"},
+	}
+
+	for _, c := range cases {
+		endpoint, err := provider.ResolveEndpoint(c.endpoint, c.regionGroup)
+		if err != nil {
+			t.Fatalf("Expected no error, but got: %s", err)
+		}
+		if endpoint != c.expected {
+			t.Errorf("Expected endpoint '%s', but got '%s'.", c.expected, endpoint)
+		}
+	}
+
+	if _, err := provider.ResolveEndpoint("", "mars"); err == nil {
+		t.Error("Expected an unknown region group to be rejected.")
+	}
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	valid := []string{"https://db.fauna.com", "http://localhost:8443", "https://db.us.fauna.com/"}
+	for _, endpoint := range valid {
+		if err := provider.CheckEndpoint(endpoint); err != nil {
+			t.Errorf("Expected '%s' to be valid, but got: %s", endpoint, err)
+		}
+	}
+
+	invalid := []string{"db.fauna.com", "ftp://db.fauna.com", "https://", "http://localhost:", "http://localhost:99999", "http://localhost:port"}
+	for _, endpoint := range invalid {
+		if err := provider.CheckEndpoint(endpoint); err == nil {
+			t.Errorf("Expected '%s' to be invalid.", endpoint)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Profile holds the connection settings read from a Fauna shell or Fauna CLI configuration file.
+type Profile struct {
+	Secret   string
+	Endpoint string
+	Database string
+}
+
+// DefaultConfigFiles lists the configuration files searched for a profile when `config_file` is
+// not set, in order of precedence.
+func DefaultConfigFiles() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	return []string{
+		filepath.Join(home, ".fauna-shell"),
+		filepath.Join(home, ".fauna", "config.yaml"),
+		filepath.Join(home, ".fauna", "config.yml"),
+	}
+}
+
+// FindConfigFile returns the first of the default configuration files that exists.
+func FindConfigFile() (string, error) {
+	for _, path := range DefaultConfigFiles() {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("No Fauna configuration file was found. Searched: %s.", strings.Join(DefaultConfigFiles(), ", "))
+}
+
+// LoadProfile reads the profile called `name` from the configuration file at `path`. Files with a
+// `.yaml` or `.yml` extension are read in the Fauna CLI format, and all others in the Fauna shell
+// INI format. An empty `name` selects the file's default profile.
+func LoadProfile(path string, name string) (*Profile, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the Fauna configuration file '%s': %s", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseCLIProfile(path, contents, name)
+	default:
+		return parseShellProfile(path, contents, name)
+	}
+}
+
+// parseShellProfile reads a profile from a Fauna shell configuration file, which looks like:
+//
+//	default=cloud
+//
+//	[cloud]
+//	domain=db.fauna.com
+//	scheme=https
+//	secret=...
+func parseShellProfile(path string, contents []byte, name string) (*Profile, error) {
+	sections := map[string]map[string]string{"": {}}
+	section := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(string(contents)))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]") {
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			if _, ok := sections[section]; !ok {
+				sections[section] = map[string]string{}
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("Failed to parse the Fauna configuration file '%s': line %d is neither a section nor a key-value pair.", path, line)
+		}
+
+		sections[section][strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	if name == "" {
+		name = sections[""]["default"]
+		if name == "" {
+			return nil, fmt.Errorf("No profile was specified, and the Fauna configuration file '%s' does not declare a default one.", path)
+		}
+	}
+
+	settings, ok := sections[name]
+	if !ok || name == "" {
+		delete(sections, "")
+		return nil, profileNotFound(path, name, keys(sections))
+	}
+
+	profile := &Profile{Secret: settings["secret"], Database: settings["database"]}
+
+	if domain := settings["domain"]; domain != "" {
+		scheme := settings["scheme"]
+		if scheme == "" {
+			scheme = "https"
+		}
+
+		profile.Endpoint = fmt.Sprintf("%s://%s", scheme, domain)
+		if port := settings["port"]; port != "" {
+			profile.Endpoint = fmt.Sprintf("%s:%s", profile.Endpoint, port)
+		}
+	}
+
+	return profile, nil
+}
+
+type cliProfile struct {
+	Secret   string `yaml:"secret"`
+	URL      string `yaml:"url"`
+	Local    bool   `yaml:"local"`
+	Database string `yaml:"database"`
+}
+
+// parseCLIProfile reads a profile from a Fauna CLI configuration file, which looks like:
+//
+//	default:
+//	  database: us/my_database
+//	  secret: ...
+//
+// The first segment of `database` names the region group of the database.
+func parseCLIProfile(path string, contents []byte, name string) (*Profile, error) {
+	profiles := map[string]cliProfile{}
+	if err := yaml.Unmarshal(contents, &profiles); err != nil {
+		return nil, fmt.Errorf("Failed to parse the Fauna configuration file '%s': %s", path, err)
+	}
+
+	if name == "" {
+		name = "default"
+	}
+
+	settings, ok := profiles[name]
+	if !ok {
+		return nil, profileNotFound(path, name, keys(profiles))
+	}
+
+	profile := &Profile{Secret: settings.Secret, Endpoint: settings.URL, Database: settings.Database}
+
+	if settings.Local && profile.Endpoint == "" {
+		profile.Endpoint = RegionGroupEndpoints["local"]
+	}
+
+	if regionGroup, database, _ := strings.Cut(profile.Database, "/"); regionGroup != "" {
+		if endpoint, ok := cliRegionGroupEndpoint(regionGroup); ok {
+			profile.Database = database
+			if profile.Endpoint == "" {
+				profile.Endpoint = endpoint
+			}
+		}
+	}
+
+	return profile, nil
+}
+
+// The Fauna CLI refers to the classic region group as 'global'.
+func cliRegionGroupEndpoint(regionGroup string) (string, bool) {
+	if regionGroup == "global" {
+		regionGroup = "classic"
+	}
+
+	endpoint, ok := RegionGroupEndpoints[regionGroup]
+	return endpoint, ok
+}
+
+func profileNotFound(path string, name string, available []string) error {
+	if len(available) == 0 {
+		return fmt.Errorf("The profile '%s' was not found in the Fauna configuration file '%s', which does not declare any profiles.", name, path)
+	}
+
+	return fmt.Errorf("The profile '%s' was not found in the Fauna configuration file '%s'. Available profiles: %s.", name, path, strings.Join(available, ", "))
+}
+
+func keys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,93 @@
+package provider_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider"
+)
+
+const shellConfig = `default=cloud
+
+[cloud]
+domain=db.fauna.com
+scheme=https
+secret=cloud_secret
+
+[localhost]
+domain=127.0.0.1
+port=8443
+scheme=http
+secret=local_secret
+`
+
+const cliConfig = `default:
+  database: us/app/staging
+  secret: default_secret
+local:
+  local: true
+  secret: local_secret
+`
+
+func writeConfigFile(t *testing.T, name string, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadProfile_shell(t *testing.T) {
+	path := writeConfigFile(t, ".fauna-shell", shellConfig)
+
+	profile, err := provider.LoadProfile(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Secret != "cloud_secret" || profile.Endpoint != "https://db.fauna.com" {
+		t.Errorf("Unexpected default profile: %+v", profile)
+	}
+
+	profile, err = provider.LoadProfile(path, "localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Secret != "local_secret" || profile.Endpoint != "http://127.0.0.1:8443" {
+		t.Errorf("Unexpected 'localhost' profile: %+v", profile)
+	}
+}
+
+func TestLoadProfile_cli(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", cliConfig)
+
+	profile, err := provider.LoadProfile(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Secret != "default_secret" || profile.Endpoint != "https://db.us.fauna.com" || profile.Database != "app/staging" {
+		t.Errorf("Unexpected default profile: %+v", profile)
+	}
+
+	profile, err = provider.LoadProfile(path, "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Secret != "local_secret" || profile.Endpoint != "http://localhost:8443" {
+		t.Errorf("Unexpected 'local' profile: %+v", profile)
+	}
+}
+
+func TestLoadProfile_missing(t *testing.T) {
+	for _, path := range []string{writeConfigFile(t, ".fauna-shell", shellConfig), writeConfigFile(t, "config.yml", cliConfig)} {
+		_, err := provider.LoadProfile(path, "production")
+		if err == nil {
+			t.Fatalf("Expected a missing profile in '%s' to be reported.", path)
+		}
+		if !strings.Contains(err.Error(), "'production'") || !strings.Contains(err.Error(), "Available profiles") {
+			t.Errorf("Expected the error to name the profile and the available ones, but got: %s", err)
+		}
+	}
+}
@@ -2,12 +2,23 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	f "github.com/fauna/faunadb-go/v5/faunadb"
 
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
 	resources "github.com/wordcollector/terraform-provider-fauna/internal/provider/resources"
 )
 
@@ -19,16 +30,259 @@ func Provider() *schema.Provider {
 	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"secret": {
+				Description:   "The secret used to authenticate with Fauna. Can also be set with the `FAUNA_SECRET`, `FAUNA_KEY` or `FAUNA` environment variables, or read from `secret_file`, `secret_command` or `profile`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("FAUNA_SECRET", schema.EnvDefaultFunc("FAUNA_KEY", schema.EnvDefaultFunc("FAUNA", nil))),
+				ConflictsWith: []string{"secret_file", "secret_command"},
+			},
+			"secret_file": {
+				Description:   "The path to a file to read the secret from. Surrounding whitespace is ignored. Conflicts with `secret` and `secret_command`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"secret", "secret_command"},
+			},
+			"secret_command": {
+				Description:   "A command, as a list of the program and its arguments, to execute to obtain the secret, such as a password manager CLI. The secret is read from its standard output, ignoring surrounding whitespace. Conflicts with `secret` and `secret_file`.",
+				Type:          schema.TypeList,
+				Optional:      true,
+				MinItems:      1,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"secret", "secret_file"},
+			},
+			"secret_command_timeout": {
+				Description:      "How long to wait for `secret_command` to complete, as a duration such as `30s`. Defaults to `30s`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "30s",
+				ValidateDiagFunc: validateDuration,
+			},
+			"http_timeout": {
+				Description:      "The maximum duration of an HTTP request to Fauna, including reading the response, such as `1m`. Unlimited by default.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateDuration,
+			},
+			"proxy_url": {
+				Description:      "The URL of the proxy to send requests to Fauna through. Defaults to the proxy given by the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithScheme([]string{"http", "https", "socks5"})),
+			},
+			"ca_cert_pem": {
+				Description:   "PEM-encoded certificates of the authorities to trust when verifying the endpoint, in addition to those of the system. Conflicts with `ca_cert_file`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_file"},
+			},
+			"ca_cert_file": {
+				Description:   "The path to a file of PEM-encoded certificates of the authorities to trust when verifying the endpoint, in addition to those of the system. Conflicts with `ca_cert_pem`.",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_pem"},
+			},
+			"insecure_skip_verify": {
+				Description: "Whether to skip the verification of the endpoint's TLS certificate. Only intended for local development containers.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"max_idle_connections": {
+				Description:      "The maximum number of idle connections to Fauna kept open for reuse.",
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+			"query_timeout": {
+				Description:      "The server-side timeout of every query sent to Fauna, such as `30s`. Defaults to Fauna's default of `60s`.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateDuration,
+			},
+			"headers": {
+				Description: "HTTP headers sent with every query.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"query_tags": {
+				Description:      "Query tags attached to every query, such as the identifier of the pipeline run, to filter Fauna's logs by. The resource type (`tf_resource_type`), the name of the Fauna object (`tf_object_name`) and the operation (`tf_operation`) of each query are tagged automatically. Keys and values may only contain letters, digits and underscores.",
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ValidateDiagFunc: validateQueryTags,
+			},
+			"read_only": {
+				Description: "Whether the provider may only read resources. Creating, updating or deleting any resource fails, which guards plans run with privileged secrets, such as drift detection, against being applied.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"read_only_plan_check": {
+				Description: "Whether `read_only` also fails plans that create or update resources, rather than only their application. Deletions are always only refused when applied.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"allowed_databases": {
+				Description: "Patterns of the paths of the databases that resources may be created, updated or deleted in, relative to the database the key was created in. A `fauna_database` is checked against its own path. Within a segment of the path, `*` matches any characters and `?` matches a single one, a `**` segment matches any number of segments, and `/` matches the database the key was created in. Any database is allowed if unset.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateDiagFunc: validateDatabasePattern},
+			},
+			"denied_databases": {
+				Description: "Patterns of the paths of the databases that resources may not be created, updated or deleted in, taking precedence over `allowed_databases`. Uses the same syntax as `allowed_databases`.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateDiagFunc: validateDatabasePattern},
+			},
+			"naming_rules": {
+				Description: "Naming conventions that the names of new, renamed or replaced resources of a type must follow, checked at plan time in addition to Fauna's own naming rules. Several rules for the same type must all be followed.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"resource_type": {
+							Description:      fmt.Sprintf("The type of resource the rule applies to. One of: %s.", strings.Join(ResourceTypes, ", ")),
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(ResourceTypes, false)),
+						},
+						"pattern": {
+							Description:      "A regular expression, in the syntax of Go's `regexp` package, that names must match. Unanchored unless it starts with `^` and ends with `$`.",
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsValidRegExp),
+						},
+						"prefix": {
+							Description: "The prefix that names must start with.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"suffix": {
+							Description: "The suffix that names must end with.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"adopt_existing": {
+				Description: "Whether resources take over existing Fauna objects with the same name, updating them to match the configuration, rather than failing to create them. Can be overridden by the `adopt_existing` attribute of each resource.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"audit_log_path": {
+				Description: "The path of a file to append a JSON record to for every query that writes to Fauna to create, update or delete a resource, with the time, the Fauna object, the operation, the database, the query with secrets masked, the timestamp of the result or the error, and `audit_operator`. Each record is flushed to disk as soon as the query completes.",
 				Type:        schema.TypeString,
-				Required:    true,
-				Sensitive:   true,
-				DefaultFunc: schema.EnvDefaultFunc("FAUNA_SECRET", schema.EnvDefaultFunc("FAUNA_KEY", schema.EnvDefaultFunc("FAUNA", nil))),
+				Optional:    true,
+			},
+			"audit_operator": {
+				Description: fmt.Sprintf("The identity of whoever runs Terraform, recorded in `audit_log_path`. Defaults to the first of the %s environment variables that is set.", formatList(AuditOperatorEnvVars)),
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.MultiEnvDefaultFunc(AuditOperatorEnvVars, nil),
+			},
+			"metrics_file": {
+				Description: "The path of a JSON file to write the query metrics reported by Fauna (compute, byte read and byte write ops) to, in total and for each Fauna object, identified as `<resource type>.<name of the object>`, since providers cannot see the address of resources. The file is updated after every resource operation. Terraform runs a plugin process for the plan, another for the apply, and one for each provider configuration, so each adds its metrics under `processes`, which `total` and `objects` sum. The file accumulates the metrics of successive runs, so remove it before a run to report that run only.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"report_operation_metrics": {
+				Description: "Whether every creation, update or deletion of a resource reports the query metrics it consumed, and those of the whole run so far, as a warning of its own. Terraform does not let providers report once at the end of a run, so use `metrics_file` for a single report.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"tracing": {
+				Description: "Exports OpenTelemetry spans for every resource operation, with a child span for each query, which carry the resource, the database and the query metrics. The trace context is propagated to Fauna through the `traceparent` header. Spans are exported in batches, the last of which when Terraform stops the provider at the end of the run.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"otlp_endpoint": {
+							Description:      "The URL of the OTLP/HTTP endpoint to send spans to, such as `http://localhost:4318`. The path defaults to `/v1/traces`.",
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithHTTPorHTTPS),
+						},
+						"otlp_headers": {
+							Description: "HTTP headers sent with every request to `otlp_endpoint`, such as credentials.",
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Sensitive:   true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"file": {
+							Description: "The path of a file to append spans to, one JSON object per span.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"skip_credentials_validation": {
+				Description: "Whether to skip verifying the secret and its permissions with a query when configuring the provider.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"profile": {
+				Description: "The name of the profile in `config_file` to read the secret, endpoint and database from. Settings given directly to the provider take precedence over those of the profile. Can also be set with the `FAUNA_PROFILE` environment variable.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FAUNA_PROFILE", nil),
+			},
+			"config_file": {
+				Description: "The path to a Fauna shell (INI) or Fauna CLI (YAML, by the `.yaml` or `.yml` extension) configuration file to read `profile` from. Defaults to the first of `~/.fauna-shell`, `~/.fauna/config.yaml` and `~/.fauna/config.yml` that exists. Can also be set with the `FAUNA_CONFIG_FILE` environment variable.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("FAUNA_CONFIG_FILE", nil),
+			},
+			"database": {
+				Description:      "The slash-separated path of the child database to manage resources in, such as `app/staging`. The secret is scoped to this database with the `admin` role, so it must be an admin secret of the parent database. Takes precedence over the database of `profile`. Can also be set with the `FAUNA_DATABASE` environment variable.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FAUNA_DATABASE", nil),
+				ValidateDiagFunc: validateDatabasePath,
+			},
+			"default_data": {
+				Description: "Metadata merged into the `data` of every resource managed by this provider. Resources can override a default by setting the same key in their own `data`.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"data": {
+							Description: "The default metadata.",
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
 			},
 			"endpoint": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Description:      "The URL of the Fauna endpoint to send queries to. Takes precedence over `region_group`. Can also be set with the `FAUNA_ENDPOINT` environment variable.",
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FAUNA_ENDPOINT", nil),
+				ValidateDiagFunc: validateEndpoint,
+			},
+			"region_group": {
+				Description:      fmt.Sprintf("The region group of the Fauna database, used to determine the endpoint when `endpoint` is not set. One of: %s. Can also be set with the `FAUNA_REGION_GROUP` environment variable.", strings.Join(RegionGroups, ", ")),
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("FAUNA_REGION_GROUP", nil),
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(RegionGroups, false)),
 			},
 		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"fauna_current_key": resources.DataSourceCurrentKey(),
+		},
 		ResourcesMap: map[string]*schema.Resource{
 			"fauna_collection": resources.ResourceCollection(),
 			"fauna_database":   resources.ResourceDatabase(),
@@ -42,20 +296,467 @@ func Provider() *schema.Provider {
 	return provider
 }
 
+// The environment variables that identify whoever runs Terraform, in order of precedence: an
+// explicit identity, then the user who triggered the CI pipeline, then the local user.
+var AuditOperatorEnvVars = []string{"FAUNA_AUDIT_OPERATOR", "GITHUB_ACTOR", "GITLAB_USER_LOGIN", "BUILD_REQUESTEDFOR", "USER", "USERNAME"}
+
+// The types of resource managed by the provider.
+var ResourceTypes = []string{"fauna_collection", "fauna_database", "fauna_function", "fauna_index"}
+
 func configure(provider *schema.Provider) func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	return func(ctx context.Context, data *schema.ResourceData) (interface{}, diag.Diagnostics) {
-		var diags diag.Diagnostics
+		secret, diags := resolveSecret(ctx, data)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		endpoint, err := ResolveEndpoint(data.Get("endpoint").(string), data.Get("region_group").(string))
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+
+		profile, profileDiags := loadProfile(data)
+		diags = append(diags, profileDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
 
-		secret := data.Get("secret").(string)
+		database := data.Get("database").(string)
+		if profile != nil {
+			if secret == "" {
+				secret = profile.Secret
+			}
 
-		var client *f.FaunaClient
+			if endpoint == "" {
+				endpoint = profile.Endpoint
+			}
+
+			if database == "" {
+				database = profile.Database
+			}
+		}
 
-		if endpoint := data.Get("endpoint").(string); endpoint != "" {
-			client = f.NewFaunaClient(secret, f.Endpoint(endpoint))
-		} else {
-			client = f.NewFaunaClient(secret)
+		if secret == "" {
+			return nil, append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Missing Fauna secret",
+				Detail:   "No secret was configured. Set `secret`, `secret_file`, `secret_command`, one of the `FAUNA_SECRET`, `FAUNA_KEY` or `FAUNA` environment variables, or a `profile` that declares a secret.",
+			})
 		}
 
-		return client, diags
+		scopedDatabase, role := ParseScopedSecret(secret)
+
+		secret, err = ScopeSecret(secret, database, "admin")
+		if err != nil {
+			return nil, append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Failed to scope secret",
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("database"),
+			})
+		}
+
+		transport, transportDiags := transportConfig(data)
+		diags = append(diags, transportDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		// Without any transport setting, the Fauna client's own HTTP client is used.
+		var httpClient *http.Client
+		if !transport.IsDefault() {
+			httpClient, err = NewHTTPClient(transport)
+			if err != nil {
+				return nil, append(diags, diag.FromErr(err)...)
+			}
+		}
+
+		config := client.Config{
+			Secret:      secret,
+			Endpoint:    endpoint,
+			HTTP:        httpClient,
+			Headers:     stringMap(data.Get("headers")),
+			QueryTags:   stringMap(data.Get("query_tags")),
+			DefaultData: map[string]any{},
+			Database:    scopedDatabase,
+			Role:        role,
+
+			ReadOnly:          data.Get("read_only").(bool),
+			ReadOnlyPlanCheck: data.Get("read_only_plan_check").(bool),
+			DatabaseRules: client.DatabaseRules{
+				Allowed: stringList(data.Get("allowed_databases")),
+				Denied:  stringList(data.Get("denied_databases")),
+			},
+			NamingRules: namingRules(data.Get("naming_rules")),
+
+			AdoptExisting:          data.Get("adopt_existing").(bool),
+			MetricsFile:            data.Get("metrics_file").(string),
+			ReportOperationMetrics: data.Get("report_operation_metrics").(bool),
+		}
+
+		if database != "" {
+			config.Database, config.Role = strings.Trim(database, "/"), "admin"
+		}
+
+		if timeout := data.Get("query_timeout").(string); timeout != "" {
+			config.QueryTimeout, _ = time.ParseDuration(timeout)
+		}
+
+		if defaultData, ok := data.Get("default_data").([]any); ok && len(defaultData) != 0 && defaultData[0] != nil {
+			config.DefaultData = defaultData[0].(map[string]any)["data"].(map[string]any)
+		}
+
+		if path := data.Get("audit_log_path").(string); path != "" {
+			config.AuditLog, err = client.OpenAuditLog(path, data.Get("audit_operator").(string))
+			if err != nil {
+				return nil, append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Failed to open audit log",
+					Detail:        err.Error(),
+					AttributePath: cty.GetAttrPath("audit_log_path"),
+				})
+			}
+		}
+
+		if tracing, ok := data.Get("tracing").([]any); ok && len(tracing) != 0 && tracing[0] != nil {
+			tracing := tracing[0].(map[string]any)
+
+			config.TracerProvider, err = NewTracerProvider(ctx, TracingConfig{
+				OTLPEndpoint: tracing["otlp_endpoint"].(string),
+				OTLPHeaders:  stringMap(tracing["otlp_headers"]),
+				File:         tracing["file"].(string),
+			})
+			if err != nil {
+				return nil, append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       "Failed to configure tracing",
+					Detail:        err.Error(),
+					AttributePath: cty.GetAttrPath("tracing"),
+				})
+			}
+
+			if config.TracerProvider != nil {
+				RegisterTracerProvider(config.TracerProvider)
+			}
+		}
+
+		meta := client.New(config)
+
+		if !data.Get("skip_credentials_validation").(bool) {
+			key, err := meta.Probe(ctx)
+			if err != nil {
+				return nil, append(diags, probeDiagnostic(err, meta.Endpoint(), config.Database))
+			}
+
+			if key.Role != client.RoleAdmin {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Fauna secret is not an admin secret",
+					Detail:   fmt.Sprintf("The secret has the '%s' role, so it cannot manage `fauna_database` resources or child databases. Use an admin secret to manage every kind of resource.", key.Role),
+				})
+			}
+		}
+
+		return meta, diags
+	}
+}
+
+// loadProfile reads the profile selected by `profile` and `config_file`, if either is set.
+func loadProfile(data *schema.ResourceData) (*Profile, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	name := data.Get("profile").(string)
+	path := data.Get("config_file").(string)
+
+	if name == "" && path == "" {
+		return nil, diags
+	}
+
+	if path == "" {
+		found, err := FindConfigFile()
+		if err != nil {
+			return nil, append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Fauna configuration file not found",
+				Detail:        fmt.Sprintf("Cannot read the profile '%s': %s", name, err),
+				AttributePath: cty.GetAttrPath("config_file"),
+			})
+		}
+
+		path = found
+	}
+
+	profile, err := LoadProfile(path, name)
+	if err != nil {
+		return nil, append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Failed to load Fauna profile",
+			Detail:        err.Error(),
+			AttributePath: cty.GetAttrPath("profile"),
+		})
+	}
+
+	return profile, diags
+}
+
+// resolveSecret obtains the secret from `secret_file` or `secret_command` if either is set, and
+// from `secret` otherwise. Since the secret may come from an external program, it is redacted from
+// any diagnostic.
+func resolveSecret(ctx context.Context, data *schema.ResourceData) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if path := data.Get("secret_file").(string); path != "" {
+		secret, err := ReadSecretFile(path)
+		if err != nil {
+			return "", append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Failed to read secret file",
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("secret_file"),
+			})
+		}
+
+		return secret, diags
+	}
+
+	if command := data.Get("secret_command").([]any); len(command) != 0 {
+		args := make([]string, len(command))
+		for i, arg := range command {
+			args[i], _ = arg.(string)
+		}
+
+		timeout, _ := time.ParseDuration(data.Get("secret_command_timeout").(string))
+
+		secret, err := RunSecretCommand(ctx, args, timeout)
+		if err != nil {
+			return "", append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Failed to run secret command",
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("secret_command"),
+			})
+		}
+
+		return secret, diags
+	}
+
+	return data.Get("secret").(string), diags
+}
+
+func validateDuration(value any, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if duration, err := time.ParseDuration(value.(string)); err != nil || duration <= 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Invalid duration",
+			Detail:        fmt.Sprintf("Expected a positive duration such as '30s', but got '%s'.", value),
+			AttributePath: path,
+		})
+	}
+
+	return diags
+}
+
+func validateDatabasePath(value any, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, name := range strings.Split(strings.Trim(value.(string), "/"), "/") {
+		if err := resources.CheckName(name, "database"); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid database path",
+				Detail:        fmt.Sprintf("The database path '%s' is invalid: %s", value, err),
+				AttributePath: path,
+			})
+		}
+	}
+
+	return diags
+}
+
+func transportConfig(data *schema.ResourceData) (TransportConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	config := TransportConfig{
+		ProxyURL:           data.Get("proxy_url").(string),
+		CACertPEM:          []byte(data.Get("ca_cert_pem").(string)),
+		InsecureSkipVerify: data.Get("insecure_skip_verify").(bool),
+		MaxIdleConnections: data.Get("max_idle_connections").(int),
+	}
+
+	if timeout := data.Get("http_timeout").(string); timeout != "" {
+		config.Timeout, _ = time.ParseDuration(timeout)
+	}
+
+	if path := data.Get("ca_cert_file").(string); path != "" {
+		certs, err := ReadCACertFile(path)
+		if err != nil {
+			return config, append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Failed to read CA certificate file",
+				Detail:        err.Error(),
+				AttributePath: cty.GetAttrPath("ca_cert_file"),
+			})
+		}
+
+		config.CACertPEM = certs
+	}
+
+	if config.InsecureSkipVerify {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Warning,
+			Summary:       "TLS certificate verification is disabled",
+			Detail:        "`insecure_skip_verify` is set, so the identity of the Fauna endpoint is not verified. Only use this with local development containers.",
+			AttributePath: cty.GetAttrPath("insecure_skip_verify"),
+		})
+	}
+
+	return config, diags
+}
+
+func validateQueryTags(value any, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	tags := stringMap(value)
+	if len(tags) > client.MaximumTags-client.AutomaticTags {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Too many query tags",
+			Detail:        fmt.Sprintf("Fauna accepts up to %d query tags, of which %d are set by the provider.", client.MaximumTags, client.AutomaticTags),
+			AttributePath: path,
+		})
+	}
+
+	for _, key := range keys(tags) {
+		if err := client.CheckTag(key, tags[key]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid query tag",
+				Detail:        err.Error(),
+				AttributePath: path.IndexString(key),
+			})
+		}
+	}
+
+	return diags
+}
+
+func validateDatabasePattern(value any, path cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, segment := range strings.Split(strings.Trim(value.(string), "/"), "/") {
+		if _, err := filepath.Match(segment, ""); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid database pattern",
+				Detail:        fmt.Sprintf("The database pattern '%s' is malformed: %s", value, err),
+				AttributePath: path,
+			})
+		}
+	}
+
+	return diags
+}
+
+// namingRules groups the `naming_rules` blocks by the type of resource they apply to.
+func namingRules(value any) map[string][]client.NamingRule {
+	rules := map[string][]client.NamingRule{}
+	if blocks, ok := value.([]any); ok {
+		for _, block := range blocks {
+			block, _ := block.(map[string]any)
+			if block == nil {
+				continue
+			}
+
+			rule := client.NamingRule{}
+			rule.Prefix, _ = block["prefix"].(string)
+			rule.Suffix, _ = block["suffix"].(string)
+
+			// The pattern has been validated with the rest of the provider configuration.
+			if pattern, _ := block["pattern"].(string); pattern != "" {
+				rule.Pattern = regexp.MustCompile(pattern)
+			}
+
+			resourceType, _ := block["resource_type"].(string)
+			rules[resourceType] = append(rules[resourceType], rule)
+		}
+	}
+
+	return rules
+}
+
+// formatList formats `values` as a list of code spans, such as "`a`, `b` and `c`".
+func formatList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("`%s`", value)
+	}
+
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+
+	return fmt.Sprintf("%s and %s", strings.Join(quoted[:len(quoted)-1], ", "), quoted[len(quoted)-1])
+}
+
+func stringList(value any) []string {
+	var converted []string
+	if values, ok := value.([]any); ok {
+		for _, value := range values {
+			value, _ := value.(string)
+			converted = append(converted, value)
+		}
+	}
+
+	return converted
+}
+
+func stringMap(value any) map[string]string {
+	converted := map[string]string{}
+	if values, ok := value.(map[string]any); ok {
+		for key, value := range values {
+			converted[key], _ = value.(string)
+		}
+	}
+
+	return converted
+}
+
+// probeDiagnostic explains why the secret could not be verified.
+func probeDiagnostic(err error, endpoint string, database string) diag.Diagnostic {
+	scope := "the database the key was created in"
+	if database != "" {
+		scope = fmt.Sprintf("the database '%s'", database)
+	}
+
+	var urlErr *url.Error
+
+	switch {
+	case errors.As(err, &f.Unauthorized{}):
+		return diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid Fauna secret",
+			Detail:   fmt.Sprintf("Fauna at '%s' rejected the secret for %s. Check that the secret has not been revoked, that it belongs to the region group of the endpoint, and that the database exists.", endpoint, scope),
+		}
+	case errors.Is(err, client.ErrSchemaAccessDenied):
+		return diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Insufficient Fauna permissions",
+			Detail:   fmt.Sprintf("The secret is not allowed to read the schema of %s, so it cannot manage any resource. Use an admin or server secret.", scope),
+		}
+	case errors.As(err, &urlErr):
+		return diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "Fauna endpoint unreachable",
+			Detail:        fmt.Sprintf("Failed to reach Fauna at '%s': %s", endpoint, urlErr.Err),
+			AttributePath: cty.GetAttrPath("endpoint"),
+		}
+	default:
+		return diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Failed to verify Fauna secret",
+			Detail:   err.Error(),
+		}
 	}
 }
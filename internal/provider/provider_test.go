@@ -0,0 +1,26 @@
+package provider_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider"
+)
+
+func TestProviderDatabaseValidation(t *testing.T) {
+	validate := provider.Provider().Schema["database"].ValidateDiagFunc
+	path := cty.GetAttrPath("database")
+
+	for _, database := range []string{"app", "app/staging", "/app/staging/", "app/staging-1"} {
+		if diags := validate(database, path); diags.HasError() {
+			t.Errorf("Expected '%s' to be a valid database path, but got: %v", database, diags)
+		}
+	}
+
+	for _, database := range []string{"app//staging", "app/staging%1", "app/self"} {
+		if diags := validate(database, path); !diags.HasError() {
+			t.Errorf("Expected '%s' to be an invalid database path.", database)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+// AdoptExistingSchema describes the `adopt_existing` attribute of a resource of type
+// `resourceType`.
+func AdoptExistingSchema(resourceType string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("Whether to take over an existing %s with the same name, updating it to match the configuration, rather than failing to create it. Attributes that Fauna cannot update, such as the `terms` of an index, are planned for replacement afterwards if they differ. Defaults to the `adopt_existing` attribute of the provider.", resourceType),
+		Type:        schema.TypeBool,
+		Optional:    true,
+	}
+}
+
+// shouldAdopt reports whether a resource whose creation failed with `err` should take over the
+// existing object instead.
+func shouldAdopt(data *schema.ResourceData, conn *client.Client, err error) bool {
+	if !errors.As(err, &f.InstanceAlreadyExistsError{}) {
+		return false
+	}
+
+	if adopt := data.GetRawConfig().GetAttr("adopt_existing"); !adopt.IsNull() {
+		return adopt.True()
+	}
+
+	return conn.AdoptExisting()
+}
+
+// adopt takes over the existing object `ref`, updating it to match `object`, the object it would
+// have been created with. Keys of its `data` that are not configured, and the `nullable`
+// properties that are not set, are removed so that the object matches the configuration exactly.
+func adopt(ctx context.Context, conn *client.Client, ref f.Expr, data *schema.ResourceData, object f.Obj, nullable []string) (f.Value, error) {
+	res, err := conn.Query(ctx, f.Get(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	var existing f.ObjectV
+	if err := res.Get(&existing); err != nil {
+		return nil, err
+	}
+
+	update := f.Obj{}
+	for key, value := range object {
+		if key != "name" {
+			update[key] = value
+		}
+	}
+
+	for _, property := range nullable {
+		if unsetInConfig(data, property) {
+			update[property] = f.Null()
+		}
+	}
+
+	configured, _ := data.Get("data_all").(map[string]any)
+	merged := make(map[string]any, len(configured))
+	for key, value := range configured {
+		merged[key] = value
+	}
+
+	existingData, _ := GetProperty(existing, "data", map[string]any{})
+	for key := range existingData {
+		if _, ok := merged[key]; !ok {
+			merged[key] = f.Null()
+		}
+	}
+
+	update["data"] = merged
+
+	return conn.Query(ctx, f.Update(ref, update))
+}
+
+// AdoptionWarning explains that an existing resource of type `resourceType` was adopted rather
+// than created.
+func AdoptionWarning(resourceType string, name string) diag.Diagnostic {
+	return diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Adopted existing resource",
+		Detail:   fmt.Sprintf("The %s '%s' already existed, so it was adopted and updated to match the configuration rather than created (`adopt_existing`). It is now managed by Terraform, and will be deleted when the resource is destroyed.", resourceType, name),
+	}
+}
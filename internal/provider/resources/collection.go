@@ -4,34 +4,42 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
 )
 
 func ResourceCollection() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourceCollectionCreate,
-		ReadContext:   resourceCollectionRead,
-		UpdateContext: resourceCollectionUpdate,
-		DeleteContext: resourceCollectionDelete,
+		CreateContext: withOperation("fauna_collection", client.OperationCreate, resourceCollectionCreate),
+		ReadContext:   withOperation("fauna_collection", client.OperationRead, resourceCollectionRead),
+		UpdateContext: withOperation("fauna_collection", client.OperationUpdate, resourceCollectionUpdate),
+		DeleteContext: withOperation("fauna_collection", client.OperationDelete, resourceCollectionDelete),
+
+		CustomizeDiff: customizeDiff("fauna_collection", customizeCollectionImpact),
+
+		Timeouts: createTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Description: fmt.Sprintf("The name of this collection. Cannot be one of: %s.", strings.Join(BlacklistedResourceNames, ", ")),
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      fmt.Sprintf("The name of this collection. %s", DescribeNameRules("collection")),
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: ValidateName("collection"),
 			},
 			"data": {
 				Description: "Developer-defined metadata for this collection.",
 				Type:        schema.TypeMap,
 				Optional:    true,
 			},
+			"data_all": DataAllSchema("collection"),
 			"history_days": {
-				Description: "The number of days that document history is to be retained for in this collection.",
+				Description: "The number of days that document history is to be retained for in this collection. Lowering it irreversibly removes older history, so the plan shows the number of documents affected in `planned_impact`.",
 				Type:        schema.TypeInt,
 				Optional:    true,
 				Default:     0,
@@ -43,11 +51,19 @@ func ResourceCollection() *schema.Resource {
 				Default:     nil,
 			},
 			"ttl_days": {
-				Description: "The number of days documents are to be retained for in this collection.",
+				Description: "The number of days documents are to be retained for in this collection. Setting or lowering it irreversibly removes older documents, so the plan shows the number of documents affected in `planned_impact`.",
 				Type:        schema.TypeInt,
 				Optional:    true,
 				Default:     nil,
 			},
+			"adopt_existing": AdoptExistingSchema("collection"),
+			"deletion_protection": {
+				Description: "Whether this collection is protected from deletion, which irreversibly removes all of its documents. Must be set to `false` in a prior apply for the collection to be destroyed.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
+			"planned_impact": PlannedImpactSchema("collection", "the number of documents that lose their history or are removed when `history_days` or `ttl_days` are lowered"),
 			"ts": {
 				Description: "A timestamp of when this collection was created.",
 				Type:        schema.TypeInt,
@@ -57,7 +73,7 @@ func ResourceCollection() *schema.Resource {
 	}
 }
 
-func synchroniseCollectionResourceData(res f.Value, data *schema.ResourceData) error {
+func synchroniseCollectionResourceData(res f.Value, data *schema.ResourceData, meta any) error {
 	var obj f.ObjectV
 	if err := res.Get(&obj); err != nil {
 		return err
@@ -67,21 +83,18 @@ func synchroniseCollectionResourceData(res f.Value, data *schema.ResourceData) e
 		data.Set("name", name_)
 	}
 
-	if data_, ok := GetProperty(obj, "data", map[string]any{}); ok {
-		data.Set("data", data_)
-	}
+	data_, _ := GetProperty(obj, "data", map[string]any{})
+	SetData(data, data_, meta)
 
 	if historyDays, ok := GetProperty(obj, "history_days", 0); ok {
 		data.Set("history_days", historyDays)
 	}
 
-	if ttl, ok := GetProperty[any](obj, "ttl", nil); ok {
-		data.Set("ttl", ttl)
-	}
+	ttl, _ := GetProperty[any](obj, "ttl", nil)
+	data.Set("ttl", ttl)
 
-	if ttlDays, ok := GetProperty[any](obj, "ttl_days", nil); ok {
-		data.Set("ttl_days", ttlDays)
-	}
+	ttlDays, _ := GetProperty[any](obj, "ttl_days", nil)
+	data.Set("ttl_days", ttlDays)
 
 	if ts, ok := GetProperty[int64](obj, "ts", 0); ok {
 		data.SetId(strconv.FormatInt(ts, 10))
@@ -92,43 +105,61 @@ func synchroniseCollectionResourceData(res f.Value, data *schema.ResourceData) e
 }
 
 func resourceCollectionCreate(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
 	name := data.Get("name").(string)
 
-	if err := CheckNameNotBlacklisted(name, "collection"); err != nil {
+	if err := CheckName(name, "collection"); err != nil {
 		return diag.FromErr(err)
 	}
 
-	res, err := conn.Query(f.CreateCollection(f.Obj{
+	var diags diag.Diagnostics
+
+	obj := f.Obj{
 		"name":         name,
-		"data":         data.Get("data"),
+		"data":         data.Get("data_all"),
 		"history_days": data.Get("history_days"),
 		"ttl":          data.Get("ttl"),
 		"ttl_days":     data.Get("ttl_days"),
-	}))
+	}
+
+	res, err := createObject(ctx, conn, data, f.Collection(name), f.CreateCollection(obj))
+	if shouldAdopt(data, conn, err) {
+		res, err = adopt(ctx, conn, f.Collection(name), data, obj, collectionNullableProperties)
+		diags = append(diags, AdoptionWarning("fauna_collection", name))
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return QueryDiagnostics(ctx, err, ResourceCollection())
 	}
 
-	if err := synchroniseCollectionResourceData(res, data); err != nil {
+	if err := synchroniseCollectionResourceData(res, data, meta); err != nil {
 		return diag.FromErr(err)
 	}
 
-	return resourceCollectionRead(ctx, data, meta)
+	if err := waitForDefinition(ctx, conn, data, f.Collection(name), int64(data.Get("ts").(int))); err != nil {
+		return QueryDiagnostics(ctx, err, ResourceCollection())
+	}
+
+	return append(diags, resourceCollectionRead(ctx, data, meta)...)
 }
 
 func resourceCollectionRead(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	res, err := conn.Query(f.Get(f.Collection(data.Get("name"))))
+	res, err := conn.Query(ctx, f.Get(f.Collection(data.Get("name"))))
+	if IsNotFound(err) && !data.IsNewResource() {
+		// The collection was deleted outside of Terraform, or along with its parent.
+		tflog.Warn(ctx, "Collection no longer exists, removing it from the state", map[string]any{"name": data.Get("name")})
+		data.SetId("")
+		return diags
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return QueryDiagnostics(ctx, err, ResourceCollection())
 	}
 
-	if err := synchroniseCollectionResourceData(res, data); err != nil {
+	if err := synchroniseCollectionResourceData(res, data, meta); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -136,23 +167,26 @@ func resourceCollectionRead(ctx context.Context, data *schema.ResourceData, meta
 }
 
 var collectionPropertiesToCheck = []string{"name", "data", "history_days", "ttl", "ttl_days"}
+var collectionNullableProperties = []string{"history_days", "ttl", "ttl_days"}
 
 func resourceCollectionUpdate(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	object := make(map[string]any)
-	for _, property := range collectionPropertiesToCheck {
-		if !data.HasChange(property) {
-			continue
+	if data.HasChange("name") {
+		if err := CheckName(data.Get("name").(string), "collection"); err != nil {
+			return diag.FromErr(err)
 		}
-
-		object[property] = data.Get(property)
 	}
 
+	object := GetChangedProperties(data, collectionPropertiesToCheck, collectionNullableProperties)
+
 	if len(object) != 0 {
-		_, err := conn.Query(f.Update(f.Collection(data.Get("name")), object))
+		// If this collection is being renamed, it can only be referred to by its previous name.
+		previousName, _ := data.GetChange("name")
+
+		_, err := conn.Query(ctx, f.Update(f.Collection(previousName), object))
 		if err != nil {
-			return diag.FromErr(err)
+			return QueryDiagnostics(ctx, err, ResourceCollection())
 		}
 	}
 
@@ -162,14 +196,27 @@ func resourceCollectionUpdate(ctx context.Context, data *schema.ResourceData, me
 func resourceCollectionDelete(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	_, err := conn.Query(f.Delete(f.Collection(data.Get("name"))))
-	if err != nil {
-		return diag.FromErr(err)
+	if data.Get("deletion_protection").(bool) {
+		return DeletionProtectionDiagnostics("collection", data.Get("name").(string))
+	}
+
+	name := data.Get("name").(string)
+
+	// Destroying is not planned through CustomizeDiff, so unlike the impact of other changes, the
+	// documents lost along with the collection can only be reported once it is deleted.
+	impact := impactDiagnostics(ctx, conn, "fauna_collection", name, name, "Collection deleted with its documents",
+		fmt.Sprintf("The collection '%s' was deleted along with %%s.", name))
+
+	// A collection that no longer exists has been destroyed already.
+	_, err := conn.Query(ctx, f.Delete(f.Collection(name)))
+	if err != nil && !IsNotFound(err) {
+		return QueryDiagnostics(ctx, err, ResourceCollection())
 	}
 
 	data.SetId("")
+	diags = append(diags, impact...)
 
 	return diags
 }
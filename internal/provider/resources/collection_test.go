@@ -2,6 +2,7 @@ package resources_test
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -35,6 +36,131 @@ func TestAccCollection(t *testing.T) {
 					resource.TestCheckResourceAttr("fauna_collection.collection", "history_days", "30"),
 					resource.TestCheckResourceAttr("fauna_collection.collection", "ttl", "7"),
 					resource.TestCheckResourceAttr("fauna_collection.collection", "ttl_days", "14"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data.sample_key", "sample_value"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data.sample_key_2", "false"),
+				),
+			},
+			{
+				// An explicit zero is sent to Fauna rather than unsetting the property.
+				Config: testAccCollectionConfiguration_zeroHistoryDays(rColName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("fauna_collection.collection", "history_days", "0"),
+					testAccCheckCollectionHistoryDays("fauna_collection.collection", 0),
+				),
+			},
+			{
+				Config: testAccCollectionConfiguration_removedProperties(rColName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCollectionExists("fauna_collection.collection"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data.%", "1"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data.sample_key", "sample_value"),
+					resource.TestCheckNoResourceAttr("fauna_collection.collection", "data.sample_key_2"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "history_days", "0"),
+					acctest.TestAccCheckPropertiesRemoved("fauna_collection.collection", f.Collection, "data.sample_key_2", "ttl", "ttl_days"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCollection_defaultData(t *testing.T) {
+	rColName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acctest.TestAccPreCheck(t) },
+		Providers:    acctest.TestAccProviders,
+		CheckDestroy: testAccCheckCollectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCollectionConfiguration_defaultData(rColName, `owner = "platform"
+			team  = "core"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCollectionExists("fauna_collection.collection"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data.%", "2"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data.team", "search"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data_all.%", "3"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data_all.owner", "platform"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data_all.team", "search"),
+				),
+			},
+			{
+				Config: testAccCollectionConfiguration_defaultData(rColName, `team = "core"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCollectionExists("fauna_collection.collection"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data_all.%", "2"),
+					resource.TestCheckNoResourceAttr("fauna_collection.collection", "data_all.owner"),
+					acctest.TestAccCheckPropertiesRemoved("fauna_collection.collection", f.Collection, "data.owner"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCollection_readOnly(t *testing.T) {
+	rColName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acctest.TestAccPreCheck(t) },
+		Providers:    acctest.TestAccProviders,
+		CheckDestroy: testAccCheckCollectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCollectionConfiguration(rColName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCollectionExists("fauna_collection.collection"),
+				),
+			},
+			{
+				Config:      testAccCollectionConfiguration_readOnly(rColName, false),
+				ExpectError: regexp.MustCompile("Provider is read-only"),
+			},
+			{
+				Config:      testAccCollectionConfiguration_readOnly(rColName, true),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("the provider is read-only"),
+			},
+			{
+				Config: testAccCollectionConfiguration(rColName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCollectionExists("fauna_collection.collection"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "history_days", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCollection_adoptExisting(t *testing.T) {
+	rColName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acctest.TestAccPreCheck(t) },
+		Providers:    acctest.TestAccProviders,
+		CheckDestroy: testAccCheckCollectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					client := acctest.TestAccClient()
+
+					if _, err := client.Query(f.CreateCollection(f.Obj{
+						"name":         rColName,
+						"data":         f.Obj{"legacy_key": "legacy_value"},
+						"history_days": 5,
+					})); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccCollectionConfiguration_adoptExisting(rColName, false),
+				ExpectError: regexp.MustCompile("adopt_existing"),
+			},
+			{
+				Config: testAccCollectionConfiguration_adoptExisting(rColName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCollectionExists("fauna_collection.collection"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data.%", "1"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "data.sample_key", "sample_value"),
+					resource.TestCheckResourceAttr("fauna_collection.collection", "history_days", "30"),
+					acctest.TestAccCheckPropertiesRemoved("fauna_collection.collection", f.Collection, "data.legacy_key"),
 				),
 			},
 		},
@@ -52,12 +178,81 @@ func testAccCollectionConfiguration_addedProperties(rColName string) string {
 	return fmt.Sprintf(`
 resource "fauna_collection" "collection" {
 	name         = "%s"
+	data = {
+		sample_key = "sample_value"
+		sample_key_2 = false
+	}
 	history_days = 30
 	ttl = 7
 	ttl_days = 14
 }`, rColName)
 }
 
+func testAccCollectionConfiguration_zeroHistoryDays(rColName string) string {
+	return fmt.Sprintf(`
+resource "fauna_collection" "collection" {
+	name         = "%s"
+	data = {
+		sample_key = "sample_value"
+	}
+	history_days = 0
+}`, rColName)
+}
+
+func testAccCollectionConfiguration_removedProperties(rColName string) string {
+	return fmt.Sprintf(`
+resource "fauna_collection" "collection" {
+	name         = "%s"
+	data = {
+		sample_key = "sample_value"
+	}
+}`, rColName)
+}
+
+func testAccCollectionConfiguration_defaultData(rColName string, defaultData string) string {
+	return fmt.Sprintf(`
+provider "fauna" {
+	default_data {
+		data = {
+			%[2]s
+		}
+	}
+}
+
+resource "fauna_collection" "collection" {
+	name = "%[1]s"
+	data = {
+		team        = "search"
+		cost_center = "1234"
+	}
+}`, rColName, defaultData)
+}
+
+func testAccCollectionConfiguration_readOnly(rColName string, planCheck bool) string {
+	return fmt.Sprintf(`
+provider "fauna" {
+	read_only            = true
+	read_only_plan_check = %[2]t
+}
+
+resource "fauna_collection" "collection" {
+	name         = "%[1]s"
+	history_days = 30
+}`, rColName, planCheck)
+}
+
+func testAccCollectionConfiguration_adoptExisting(rColName string, adoptExisting bool) string {
+	return fmt.Sprintf(`
+resource "fauna_collection" "collection" {
+	name           = "%[1]s"
+	adopt_existing = %[2]t
+	data = {
+		sample_key = "sample_value"
+	}
+	history_days = 30
+}`, rColName, adoptExisting)
+}
+
 func testAccCheckCollectionExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		var name string
@@ -74,7 +269,7 @@ func testAccCheckCollectionExists(resourceName string) resource.TestCheckFunc {
 			}
 		}
 
-		client := acctest.TestAccProvider.Meta().(*f.FaunaClient)
+		client := acctest.TestAccClient()
 
 		if _, err := client.Query(f.Get(f.Collection(name))); err != nil {
 			return err
@@ -84,8 +279,31 @@ func testAccCheckCollectionExists(resourceName string) resource.TestCheckFunc {
 	}
 }
 
+func testAccCheckCollectionHistoryDays(resourceName string, expected int64) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		res, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourceName)
+		}
+
+		client := acctest.TestAccClient()
+
+		value, err := client.Query(f.Select("history_days", f.Get(f.Collection(res.Primary.Attributes["name"])), f.Default(f.Null())))
+		if err != nil {
+			return err
+		}
+
+		var historyDays int64
+		if err := value.Get(&historyDays); err != nil || historyDays != expected {
+			return fmt.Errorf("Expected 'history_days' to be %d in Fauna, but got %v.", expected, value)
+		}
+
+		return nil
+	}
+}
+
 func testAccCheckCollectionDestroy(s *terraform.State) error {
-	client := acctest.TestAccProvider.Meta().(*f.FaunaClient)
+	client := acctest.TestAccClient()
 
 	for _, res := range s.RootModule().Resources {
 		if res.Type != "fauna_collection" {
@@ -0,0 +1,54 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+func DataSourceCurrentKey() *schema.Resource {
+	return &schema.Resource{
+		Description: "Describes the secret the provider is configured with.",
+
+		ReadContext: dataSourceCurrentKeyRead,
+
+		Schema: map[string]*schema.Schema{
+			"role": {
+				Description: "The role of the secret: `admin`, `server`, or the role it is scoped with.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"database": {
+				Description: "The slash-separated path of the database the secret is scoped to, relative to the database the key was created in. Empty if the secret is not scoped.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+			"endpoint": {
+				Description: "The URL of the Fauna endpoint the provider sends queries to.",
+				Type:        schema.TypeString,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceCurrentKeyRead(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	conn := meta.(*client.Client)
+
+	key, err := conn.Key(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(key.Endpoint + "/" + key.Database)
+	data.Set("role", key.Role)
+	data.Set("database", key.Database)
+	data.Set("endpoint", key.Endpoint)
+
+	return diags
+}
@@ -0,0 +1,25 @@
+package resources_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	acctest "github.com/wordcollector/terraform-provider-fauna/internal/acctest"
+)
+
+func TestAccCurrentKey(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { acctest.TestAccPreCheck(t) },
+		Providers: acctest.TestAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `data "fauna_current_key" "current" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.fauna_current_key.current", "role", "admin"),
+					resource.TestCheckResourceAttrSet("data.fauna_current_key.current", "endpoint"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+// DataAllSchema describes the `data_all` attribute of a resource of type `resourceType`, holding
+// its `data` merged with the provider's `default_data`.
+func DataAllSchema(resourceType string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("The metadata of this %s, including the `default_data` of the provider.", resourceType),
+		Type:        schema.TypeMap,
+		Computed:    true,
+	}
+}
+
+// MergeDefaultData merges `data` into `defaults`, with the values in `data` taking precedence.
+func MergeDefaultData(defaults map[string]any, data map[string]any) map[string]any {
+	merged := make(map[string]any, len(defaults)+len(data))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+
+	for key, value := range data {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+// CustomizeDataAllDiff plans `data_all` as the `data` of a resource merged with the provider's
+// `default_data`, so that changing the defaults updates every resource.
+func CustomizeDataAllDiff(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	if !diff.NewValueKnown("data") {
+		return diff.SetNewComputed("data_all")
+	}
+
+	merged := MergeDefaultData(defaultData(meta), diff.Get("data").(map[string]any))
+	if reflect.DeepEqual(merged, diff.Get("data_all")) {
+		return nil
+	}
+
+	return diff.SetNew("data_all", merged)
+}
+
+// SetData synchronises `data` and `data_all` with the metadata stored in Fauna. The keys that stem
+// from the provider's `default_data` are left out of `data`, unless the resource sets them itself.
+func SetData(data *schema.ResourceData, remote map[string]any, meta any) {
+	defaults := defaultData(meta)
+	configured := data.Get("data").(map[string]any)
+
+	data_ := map[string]any{}
+	for key, value := range remote {
+		if default_, ok := defaults[key]; ok && fmt.Sprint(default_) == fmt.Sprint(value) {
+			if _, ok := configured[key]; !ok {
+				continue
+			}
+		}
+
+		data_[key] = value
+	}
+
+	data.Set("data", data_)
+	data.Set("data_all", remote)
+}
+
+// GetDataChange returns the new value of `data_all`, with keys that have been removed from it set
+// to `null` so that Fauna drops them.
+func GetDataChange(data *schema.ResourceData) f.Obj {
+	previousData, _ := data.GetChange("data")
+	previousDataAll, current := data.GetChange("data_all")
+
+	object := f.Obj{}
+	for _, previous := range []any{previousData, previousDataAll} {
+		for key := range previous.(map[string]any) {
+			object[key] = f.Null()
+		}
+	}
+
+	for key, value := range current.(map[string]any) {
+		object[key] = value
+	}
+
+	return object
+}
+
+func defaultData(meta any) map[string]any {
+	if conn, ok := meta.(*client.Client); ok && conn != nil {
+		return conn.DefaultData()
+	}
+
+	return map[string]any{}
+}
@@ -4,38 +4,59 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
 )
 
 func ResourceDatabase() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourceDatabaseCreate,
-		ReadContext:   resourceDatabaseRead,
-		UpdateContext: resourceDatabaseUpdate,
-		DeleteContext: resourceDatabaseDelete,
+		CreateContext: withOperation("fauna_database", client.OperationCreate, resourceDatabaseCreate),
+		ReadContext:   withOperation("fauna_database", client.OperationRead, resourceDatabaseRead),
+		UpdateContext: withOperation("fauna_database", client.OperationUpdate, resourceDatabaseUpdate),
+		DeleteContext: withOperation("fauna_database", client.OperationDelete, resourceDatabaseDelete),
+
+		CustomizeDiff: customizeDiff("fauna_database"),
+
+		Timeouts: createTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Description: fmt.Sprintf("The name of this database. Cannot be one of: %s.", strings.Join(BlacklistedResourceNames, ", ")),
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      fmt.Sprintf("The name of this database. %s", DescribeNameRules("database")),
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: ValidateName("database"),
 			},
 			"data": {
 				Description: "Developer-defined metadata for this database.",
 				Type:        schema.TypeMap,
 				Optional:    true,
 			},
+			"data_all": DataAllSchema("database"),
 			"ttl": {
 				Description: "A timestamp of when this database is to be removed.",
 				Type:        schema.TypeInt,
 				Optional:    true,
 				Default:     nil,
 			},
+			"adopt_existing": AdoptExistingSchema("database"),
+			"deletion_protection": {
+				Description: "Whether this database is protected from deletion, which irreversibly removes all of its contents. Must be set to `false` in a prior apply for the database to be destroyed.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+			},
+			"force_destroy": {
+				Description: "Whether this database may be destroyed while it still contains collections or child databases. Must be set to `true` in a prior apply for such a database to be destroyed.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
 			"global_id": {
 				Description: "A globally unique identifier for this database.",
 				Type:        schema.TypeString,
@@ -50,7 +71,7 @@ func ResourceDatabase() *schema.Resource {
 	}
 }
 
-func synchroniseDatabaseResourceData(res f.Value, data *schema.ResourceData) error {
+func synchroniseDatabaseResourceData(res f.Value, data *schema.ResourceData, meta any) error {
 	var obj f.ObjectV
 	if err := res.Get(&obj); err != nil {
 		return err
@@ -60,13 +81,11 @@ func synchroniseDatabaseResourceData(res f.Value, data *schema.ResourceData) err
 		data.Set("name", name_)
 	}
 
-	if data_, ok := GetProperty(obj, "data", map[string]any{}); ok {
-		data.Set("data", data_)
-	}
+	data_, _ := GetProperty(obj, "data", map[string]any{})
+	SetData(data, data_, meta)
 
-	if ttl, ok := GetProperty[any](obj, "ttl", nil); ok {
-		data.Set("ttl", ttl)
-	}
+	ttl, _ := GetProperty[any](obj, "ttl", nil)
+	data.Set("ttl", ttl)
 
 	if globalId, ok := GetProperty(obj, "global_id", ""); ok {
 		data.Set("global_id", globalId)
@@ -81,41 +100,59 @@ func synchroniseDatabaseResourceData(res f.Value, data *schema.ResourceData) err
 }
 
 func resourceDatabaseCreate(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
 	name := data.Get("name").(string)
 
-	if err := CheckNameNotBlacklisted(name, "database"); err != nil {
+	if err := CheckName(name, "database"); err != nil {
 		return diag.FromErr(err)
 	}
 
-	res, err := conn.Query(f.CreateDatabase(f.Obj{
+	var diags diag.Diagnostics
+
+	obj := f.Obj{
 		"name": name,
-		"data": data.Get("data"),
+		"data": data.Get("data_all"),
 		"ttl":  data.Get("ttl"),
-	}))
+	}
+
+	res, err := createObject(ctx, conn, data, f.Database(name), f.CreateDatabase(obj))
+	if shouldAdopt(data, conn, err) {
+		res, err = adopt(ctx, conn, f.Database(name), data, obj, databaseNullableProperties)
+		diags = append(diags, AdoptionWarning("fauna_database", name))
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return QueryDiagnostics(ctx, err, ResourceDatabase())
 	}
 
-	if err := synchroniseDatabaseResourceData(res, data); err != nil {
+	if err := synchroniseDatabaseResourceData(res, data, meta); err != nil {
 		return diag.FromErr(err)
 	}
 
-	return resourceDatabaseRead(ctx, data, meta)
+	if err := waitForDefinition(ctx, conn, data, f.Database(name), int64(data.Get("ts").(int))); err != nil {
+		return QueryDiagnostics(ctx, err, ResourceDatabase())
+	}
+
+	return append(diags, resourceDatabaseRead(ctx, data, meta)...)
 }
 
 func resourceDatabaseRead(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	res, err := conn.Query(f.Get(f.Database(data.Get("name"))))
+	res, err := conn.Query(ctx, f.Get(f.Database(data.Get("name"))))
+	if IsNotFound(err) && !data.IsNewResource() {
+		// The database was deleted outside of Terraform, or along with its parent.
+		tflog.Warn(ctx, "Database no longer exists, removing it from the state", map[string]any{"name": data.Get("name")})
+		data.SetId("")
+		return diags
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return QueryDiagnostics(ctx, err, ResourceDatabase())
 	}
 
-	if err := synchroniseDatabaseResourceData(res, data); err != nil {
+	if err := synchroniseDatabaseResourceData(res, data, meta); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -123,27 +160,26 @@ func resourceDatabaseRead(ctx context.Context, data *schema.ResourceData, meta a
 }
 
 var databasePropertiesToCheck = []string{"name", "data", "ttl"}
+var databaseNullableProperties = []string{"ttl"}
 
 func resourceDatabaseUpdate(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	object := make(map[string]any)
-	for _, property := range databasePropertiesToCheck {
-		if !data.HasChange(property) {
-			continue
+	if data.HasChange("name") {
+		if err := CheckName(data.Get("name").(string), "database"); err != nil {
+			return diag.FromErr(err)
 		}
-
-		object[property] = data.Get(property)
 	}
 
-	if data.HasChange("name") {
-		data.SetId(data.Get("name").(string))
-	}
+	object := GetChangedProperties(data, databasePropertiesToCheck, databaseNullableProperties)
 
 	if len(object) != 0 {
-		_, err := conn.Query(f.Update(f.Database(data.Get("name")), object))
+		// If this database is being renamed, it can only be referred to by its previous name.
+		previousName, _ := data.GetChange("name")
+
+		_, err := conn.Query(ctx, f.Update(f.Database(previousName), object))
 		if err != nil {
-			return diag.FromErr(err)
+			return QueryDiagnostics(ctx, err, ResourceDatabase())
 		}
 	}
 
@@ -153,11 +189,40 @@ func resourceDatabaseUpdate(ctx context.Context, data *schema.ResourceData, meta
 func resourceDatabaseDelete(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	_, err := conn.Query(f.Delete(f.Database(data.Get("name"))))
-	if err != nil {
-		return diag.FromErr(err)
+	if data.Get("deletion_protection").(bool) {
+		return DeletionProtectionDiagnostics("database", data.Get("name").(string))
+	}
+
+	if !data.Get("force_destroy").(bool) {
+		database := f.Database(data.Get("name"))
+
+		res, err := conn.Query(ctx, f.Or(
+			f.IsNonEmpty(f.Paginate(f.ScopedCollections(database), f.Size(1))),
+			f.IsNonEmpty(f.Paginate(f.ScopedDatabases(database), f.Size(1))),
+		))
+		if IsNotFound(err) {
+			data.SetId("")
+			return diags
+		}
+		if err != nil {
+			return QueryDiagnostics(ctx, err, ResourceDatabase())
+		}
+
+		if ParseFaunaValue[bool](res) {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  "Database is not empty",
+				Detail:   fmt.Sprintf("Cannot delete database '%s' because it still contains collections or child databases, which would be deleted along with it. Set `force_destroy = true` and apply before destroying it.", data.Get("name")),
+			}}
+		}
+	}
+
+	// A database that no longer exists has been destroyed already.
+	_, err := conn.Query(ctx, f.Delete(f.Database(data.Get("name"))))
+	if err != nil && !IsNotFound(err) {
+		return QueryDiagnostics(ctx, err, ResourceDatabase())
 	}
 
 	data.SetId("")
@@ -2,6 +2,7 @@ package resources_test
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -38,21 +39,72 @@ func TestAccDatabase(t *testing.T) {
 					resource.TestCheckResourceAttr("fauna_database.database", "ttl", "7"),
 				),
 			},
+			{
+				Config: testAccDatabaseConfiguration_removedProperties(rColName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatabaseExists("fauna_database.database"),
+					resource.TestCheckResourceAttr("fauna_database.database", "data.%", "1"),
+					resource.TestCheckResourceAttr("fauna_database.database", "data.sample_key", "sample_value"),
+					resource.TestCheckNoResourceAttr("fauna_database.database", "data.sample_key_2"),
+					resource.TestCheckNoResourceAttr("fauna_database.database", "data.sample_key_3"),
+					acctest.TestAccCheckPropertiesRemoved("fauna_database.database", f.Database, "data.sample_key_2", "data.sample_key_3", "ttl"),
+				),
+			},
 		},
 	})
 }
 
-func testAccDatabaseConfiguration(rColName string) string {
+func TestAccDatabase_deletionProtection(t *testing.T) {
+	rColName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acctest.TestAccPreCheck(t) },
+		Providers:    acctest.TestAccProviders,
+		CheckDestroy: testAccCheckDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabaseConfiguration_protected(rColName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatabaseExists("fauna_database.database"),
+					resource.TestCheckResourceAttr("fauna_database.database", "deletion_protection", "true"),
+				),
+			},
+			{
+				Config:      testAccDatabaseConfiguration_protected(rColName),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Deletion protection enabled"),
+			},
+			{
+				Config: testAccDatabaseConfiguration(rColName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckDatabaseExists("fauna_database.database"),
+					resource.TestCheckResourceAttr("fauna_database.database", "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDatabaseConfiguration_protected(rColName string) string {
 	return fmt.Sprintf(`
 resource "fauna_database" "database" {
 	name = "%s"
 }`, rColName)
 }
 
+func testAccDatabaseConfiguration(rColName string) string {
+	return fmt.Sprintf(`
+resource "fauna_database" "database" {
+	name                = "%s"
+	deletion_protection = false
+}`, rColName)
+}
+
 func testAccDatabaseConfiguration_addedProperties(rColName string) string {
 	return fmt.Sprintf(`
 resource "fauna_database" "database" {
 	name         = "%s"
+	deletion_protection = false
 	data = {
 		sample_key = "sample_value"
 		sample_key_2 = false
@@ -62,6 +114,17 @@ resource "fauna_database" "database" {
 }`, rColName)
 }
 
+func testAccDatabaseConfiguration_removedProperties(rColName string) string {
+	return fmt.Sprintf(`
+resource "fauna_database" "database" {
+	name         = "%s"
+	deletion_protection = false
+	data = {
+		sample_key = "sample_value"
+	}
+}`, rColName)
+}
+
 func testAccCheckDatabaseExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		var name string
@@ -78,7 +141,7 @@ func testAccCheckDatabaseExists(resourceName string) resource.TestCheckFunc {
 			}
 		}
 
-		client := acctest.TestAccProvider.Meta().(*f.FaunaClient)
+		client := acctest.TestAccClient()
 
 		if _, err := client.Query(f.Get(f.Database(name))); err != nil {
 			return err
@@ -89,7 +152,7 @@ func testAccCheckDatabaseExists(resourceName string) resource.TestCheckFunc {
 }
 
 func testAccCheckDatabaseDestroy(s *terraform.State) error {
-	client := acctest.TestAccProvider.Meta().(*f.FaunaClient)
+	client := acctest.TestAccClient()
 
 	for _, res := range s.RootModule().Resources {
 		if res.Type != "fauna_database" {
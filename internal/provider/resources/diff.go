@@ -0,0 +1,89 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+// customizeDiff returns the CustomizeDiff function shared by every resource of type `resourceType`,
+// followed by the resource's own `funcs`. The metrics file is updated with the queries they issue.
+func customizeDiff(resourceType string, funcs ...schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	sequence := customdiff.Sequence(append([]schema.CustomizeDiffFunc{
+		CustomizeDataAllDiff,
+		customizeReadOnlyDiff(resourceType),
+		customizeNameDiff(resourceType),
+	}, funcs...)...)
+
+	return func(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+		conn, ok := meta.(*client.Client)
+		if !ok {
+			return sequence(ctx, diff, meta)
+		}
+
+		ctx = conn.WithQueryLogger(ctx)
+		err := sequence(ctx, diff, meta)
+		writeMetrics(ctx, conn)
+
+		return err
+	}
+}
+
+// customizeNameDiff fails the plan of a resource that is created, renamed or replaced with a name
+// that does not follow the naming rules configured on the provider. Existing names are left alone
+// otherwise, so that rules can be introduced without replacing resources. The rules are only
+// enforced here rather than when applied, so that a replacement cannot delete a resource and then
+// fail to create it again.
+func customizeNameDiff(resourceType string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+		conn, ok := meta.(*client.Client)
+		if !ok || !diff.NewValueKnown("name") {
+			return nil
+		}
+
+		if diff.Id() != "" && !diff.HasChange("name") && !replacementPlanned(resourceType, diff) {
+			return nil
+		}
+
+		return conn.CheckNamingRules(diff.Get("name").(string), resourceType)
+	}
+}
+
+// replacementPlanned reports whether the plan replaces the existing resource of type
+// `resourceType`.
+func replacementPlanned(resourceType string, diff *schema.ResourceDiff) bool {
+	switch resourceType {
+	case "fauna_index":
+		return indexReplacementPlanned(diff)
+	default:
+		return false
+	}
+}
+
+// customizeReadOnlyDiff fails the plan of a resource with pending changes if the provider is
+// read-only and configured to enforce it at plan time. Deletions are not planned through
+// CustomizeDiff, so they are only refused when applied.
+func customizeReadOnlyDiff(resourceType string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+		conn, ok := meta.(*client.Client)
+		if !ok || !conn.ReadOnly() || !conn.ReadOnlyPlanCheck() {
+			return nil
+		}
+
+		name, _ := diff.Get("name").(string)
+
+		if diff.Id() == "" {
+			return fmt.Errorf("Cannot create %s '%s': the provider is read-only (`read_only = true`), so it cannot apply changes.", resourceType, name)
+		}
+
+		if changed := diff.GetChangedKeysPrefix(""); len(changed) != 0 {
+			return fmt.Errorf("Cannot update %s '%s': the provider is read-only (`read_only = true`), so it cannot apply changes. Changed attributes: %v.", resourceType, name, changed)
+		}
+
+		return nil
+	}
+}
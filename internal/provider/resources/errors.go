@@ -0,0 +1,166 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+// Fauna secrets, optionally scoped to a database and a role, as they may appear in error messages.
+var secretPattern = regexp.MustCompile(`fn[A-Za-z0-9_\-]{20,}(:[^\s,;"']*)?`)
+
+const redactedSecret = "[REDACTED]"
+
+// RedactSecrets replaces anything that looks like a Fauna secret in `text`.
+func RedactSecrets(text string) string {
+	return secretPattern.ReplaceAllString(text, redactedSecret)
+}
+
+// IsNotFound reports whether `err` means that the object a query refers to does not exist.
+func IsNotFound(err error) bool {
+	return errors.As(err, &f.InvalidReferenceError{}) || errors.As(err, &f.InstanceNotFoundError{})
+}
+
+// Remediation hints for common Fauna error codes, formatted with the type and the name of the
+// resource.
+var errorHints = map[string]string{
+	"permission denied":       "The secret is not allowed to manage this %s '%s'. Databases can only be managed with an admin secret, and other resources with an admin or server secret, or one whose role has privileges on the schema.",
+	"instance already exists": "A %s named '%s' already exists. Import it into the state with `terraform import`, set `adopt_existing = true` to take it over, or choose another name.",
+	"instance not found":      "An object that the %s '%s' refers to does not exist, such as the source collection of an index or the role of a function. Create it first, or make this resource depend on the one that manages it.",
+	"validation failed":       "Fauna rejected the configuration of the %s '%s'. Check the values of the attributes against the documentation of the resource.",
+	"invalid expression":      "The configuration of the %s '%s' contains an invalid FQL expression. Check the syntax of its expressions, such as the `body` of a function.",
+	"invalid argument":        "The configuration of the %s '%s' contains a value of the wrong type for Fauna.",
+}
+
+// QueryDiagnostics translates an error returned by a query issued for the operation carried by
+// `ctx` into diagnostics, pointing at the attributes of `resource` that Fauna reports as invalid,
+// with hints on how to fix common errors. Secrets are redacted from every message.
+func QueryDiagnostics(ctx context.Context, err error, resource *schema.Resource) diag.Diagnostics {
+	request, _ := client.RequestFromContext(ctx)
+
+	if errors.As(err, &f.Unauthorized{}) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Invalid Fauna secret",
+			Detail:   "Fauna rejected the secret. Check that it has not been revoked, and that it belongs to the region group of the endpoint.",
+		}}
+	}
+
+	var faunaErr f.FaunaError
+	if !errors.As(err, &faunaErr) || len(faunaErr.Errors()) == 0 {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  RedactSecrets(err.Error()),
+		}}
+	}
+
+	var diags diag.Diagnostics
+	for _, queryErr := range faunaErr.Errors() {
+		detail := RedactSecrets(queryErr.Description)
+		if hint, ok := errorHints[queryErr.Code]; ok {
+			detail = fmt.Sprintf("%s\n\n%s", detail, fmt.Sprintf(hint, request.ResourceType, request.ObjectName))
+		}
+
+		if len(queryErr.Failures) == 0 {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Fauna error: %s", queryErr.Code),
+				Detail:        detail,
+				AttributePath: AttributePath(queryErr.Position, resource),
+			})
+			continue
+		}
+
+		for _, failure := range queryErr.Failures {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       fmt.Sprintf("Fauna error: %s", queryErr.Code),
+				Detail:        fmt.Sprintf("%s: %s (%s)", detail, RedactSecrets(failure.Description), failure.Code),
+				AttributePath: AttributePath(failure.Field, resource),
+			})
+		}
+	}
+
+	return diags
+}
+
+// positionIndex parses the index of an array element within the position of an error. The Fauna
+// client decodes the numbers of positions into strings as runes, such as "\x01" for 1, whereas the
+// fields of validation failures hold digits.
+func positionIndex(segment string) (int, bool) {
+	if index, err := strconv.Atoi(segment); err == nil {
+		return index, true
+	}
+
+	if runes := []rune(segment); len(runes) == 1 {
+		return int(runes[0]), true
+	}
+
+	return 0, false
+}
+
+// AttributePath maps the position of an error within a query to the path of the attribute of
+// `resource` that the erroneous part of the query was built from, such as `terms[1].field`. The
+// position is followed as deep as the schema allows, and nil is returned if it does not refer to
+// any attribute. Nested objects are sent wrapped in an `object` literal, which positions within
+// queries go through, such as `create_index/object/terms/1/object/field`, whereas the fields of
+// validation failures do not.
+func AttributePath(position []string, resource *schema.Resource) cty.Path {
+	// The position starts with the function and its arguments, such as `create_index` and
+	// `object`, which wrap the attributes.
+	start := -1
+	for i, segment := range position {
+		if _, ok := resource.Schema[segment]; ok {
+			start = i
+			break
+		}
+	}
+
+	if start == -1 {
+		return nil
+	}
+
+	attributes := resource.Schema
+	var path cty.Path
+
+	for i := start; i < len(position); i++ {
+		attribute, ok := attributes[position[i]]
+		if !ok {
+			break
+		}
+
+		path = path.GetAttr(position[i])
+
+		nested, ok := attribute.Elem.(*schema.Resource)
+		if !ok || i+1 >= len(position) {
+			break
+		}
+
+		index, ok := positionIndex(position[i+1])
+		if !ok {
+			break
+		}
+
+		path = path.IndexInt(index)
+		attributes = nested.Schema
+		i++
+
+		if i+1 < len(position) && position[i+1] == "object" {
+			if _, ok := attributes["object"]; !ok {
+				i++
+			}
+		}
+	}
+
+	return path
+}
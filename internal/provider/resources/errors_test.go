@@ -0,0 +1,175 @@
+package resources_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider/resources"
+)
+
+type faunaError struct {
+	errors []f.QueryError
+}
+
+func (err faunaError) Error() string          { return "Response error 400." }
+func (err faunaError) HttpStatusCode() int    { return 400 }
+func (err faunaError) Errors() []f.QueryError { return err.errors }
+
+func TestAttributePath(t *testing.T) {
+	index := resources.ResourceIndex()
+	function := resources.ResourceFunction()
+
+	cases := []struct {
+		position []string
+		expected cty.Path
+	}{
+		{[]string{"create_index", "object", "terms", "\x01", "object", "field"}, cty.GetAttrPath("terms").IndexInt(1).GetAttr("field")},
+		{[]string{"create_index", "object", "terms", "1", "field"}, cty.GetAttrPath("terms").IndexInt(1).GetAttr("field")},
+		{[]string{"create_index", "object", "terms", "1", "object"}, cty.GetAttrPath("terms").IndexInt(1)},
+		{[]string{"create_index", "object", "terms"}, cty.GetAttrPath("terms")},
+		{[]string{"create_index", "object", "data", "object", "owner"}, cty.GetAttrPath("data")},
+		{[]string{"create_index", "object", "unknown"}, nil},
+	}
+
+	for _, c := range cases {
+		if path := resources.AttributePath(c.position, index); !path.Equals(c.expected) {
+			t.Errorf("Expected the position %v to map to %#v, but got %#v.", c.position, c.expected, path)
+		}
+	}
+
+	position := []string{"create_function", "object", "body", "query", "lambda"}
+	if path := resources.AttributePath(position, function); !path.Equals(cty.GetAttrPath("body")) {
+		t.Errorf("Expected the position %v to map to the body, but got %#v.", position, path)
+	}
+}
+
+func TestQueryDiagnostics(t *testing.T) {
+	ctx := client.WithRequest(context.Background(), client.Request{ResourceType: "fauna_index", ObjectName: "users_by_email", Operation: client.OperationCreate})
+
+	err := f.ValidationFailedError{FaunaError: faunaError{errors: []f.QueryError{{
+		Code:        "validation failed",
+		Description: "document data is not valid.",
+		Failures: []f.ValidationFailure{
+			{Field: []string{"terms", "0", "field"}, Code: "invalid type", Description: "Invalid type Number, expected Array."},
+		},
+	}}}}
+
+	diags := resources.QueryDiagnostics(ctx, err, resources.ResourceIndex())
+	if len(diags) != 1 {
+		t.Fatalf("Expected a diagnostic for the failure, but got %d.", len(diags))
+	}
+
+	if !diags[0].AttributePath.Equals(cty.GetAttrPath("terms").IndexInt(0).GetAttr("field")) {
+		t.Errorf("Expected the diagnostic to point at terms[0].field, but got %#v.", diags[0].AttributePath)
+	}
+
+	if !strings.Contains(diags[0].Detail, "Invalid type Number") || !strings.Contains(diags[0].Detail, "fauna_index 'users_by_email'") {
+		t.Errorf("Expected the failure and a hint in the detail, but got: %s", diags[0].Detail)
+	}
+
+	secret := "fnAEjXudojACAJYYrXAnAMA2XUZ8rs5OcZbT8Gn0:app:admin"
+	diags = resources.QueryDiagnostics(ctx, errors.New("failed to connect with "+secret), resources.ResourceIndex())
+	if strings.Contains(diags[0].Summary, "fnAEjX") || strings.Contains(diags[0].Summary, ":app:admin") {
+		t.Errorf("Expected the secret to be redacted, but got: %s", diags[0].Summary)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	undefined := faunaError{errors: []f.QueryError{{Code: "invalid ref", Description: "Ref refers to undefined index 'users_by_email'"}}}
+
+	if !resources.IsNotFound(f.InvalidReferenceError{FaunaError: undefined}) {
+		t.Error("Expected an undefined reference to mean that the object was not found.")
+	}
+
+	if !resources.IsNotFound(f.InstanceNotFoundError{FaunaError: undefined}) {
+		t.Error("Expected a missing instance to mean that the object was not found.")
+	}
+
+	if resources.IsNotFound(f.PermissionDeniedError{FaunaError: undefined}) || resources.IsNotFound(nil) {
+		t.Error("Expected other errors not to mean that the object was not found.")
+	}
+}
+
+func TestQueryDiagnostics_position(t *testing.T) {
+	// The query the provider sends to create an index with an invalid term, as configured.
+	expr := f.CreateIndex(f.Obj{
+		"name":   "users_by_email",
+		"source": f.Collection("users"),
+		"terms":  []any{map[string]any{"field": []any{"data", "email"}}, map[string]any{"field": 1}},
+	})
+
+	// The position Fauna reports the invalid term at.
+	position := []any{"create_index", "object", "terms", 1, "object", "field"}
+
+	// The position refers to the query as encoded by the Fauna client, which wraps nested objects.
+	rendered, err := json.Marshal(expr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var value any
+	if err := json.Unmarshal(rendered, &value); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, segment := range position {
+		switch segment := segment.(type) {
+		case string:
+			object, _ := value.(map[string]any)
+			value = object[segment]
+		case int:
+			array, _ := value.([]any)
+			if segment >= len(array) {
+				value = nil
+			} else {
+				value = array[segment]
+			}
+		}
+
+		if value == nil {
+			t.Fatalf("Expected the position %v to exist in the query, but it does not: %s", position, rendered)
+		}
+	}
+
+	body, err := json.Marshal(map[string]any{"errors": []any{map[string]any{
+		"position":    position,
+		"code":        "invalid argument",
+		"description": "Array or String expected, Integer provided.",
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	conn := client.New(client.Config{Secret: "secret", Endpoint: server.URL, HTTP: server.Client()})
+	ctx := client.WithRequest(context.Background(), client.Request{ResourceType: "fauna_index", ObjectName: "users_by_email", Operation: client.OperationCreate})
+
+	_, err = conn.Query(ctx, expr)
+	if err == nil {
+		t.Fatal("Expected the query to fail.")
+	}
+
+	diags := resources.QueryDiagnostics(ctx, err, resources.ResourceIndex())
+	if len(diags) != 1 {
+		t.Fatalf("Expected a diagnostic for the error, but got %d.", len(diags))
+	}
+
+	if expected := cty.GetAttrPath("terms").IndexInt(1).GetAttr("field"); !diags[0].AttributePath.Equals(expected) {
+		t.Errorf("Expected the diagnostic to point at terms[1].field, but got %#v.", diags[0].AttributePath)
+	}
+}
@@ -4,32 +4,40 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
 )
 
 func ResourceFunction() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourceFunctionCreate,
-		ReadContext:   resourceFunctionRead,
-		UpdateContext: resourceFunctionUpdate,
-		DeleteContext: resourceFunctionDelete,
+		CreateContext: withOperation("fauna_function", client.OperationCreate, resourceFunctionCreate),
+		ReadContext:   withOperation("fauna_function", client.OperationRead, resourceFunctionRead),
+		UpdateContext: withOperation("fauna_function", client.OperationUpdate, resourceFunctionUpdate),
+		DeleteContext: withOperation("fauna_function", client.OperationDelete, resourceFunctionDelete),
+
+		CustomizeDiff: customizeDiff("fauna_function"),
+
+		Timeouts: createTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Description: fmt.Sprintf("The name of this function. Cannot be one of: %s.", strings.Join(BlacklistedResourceNames, ", ")),
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      fmt.Sprintf("The name of this function. %s", DescribeNameRules("function")),
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: ValidateName("function"),
 			},
 			"data": {
 				Description: "Developer-defined metadata for this function.",
 				Type:        schema.TypeMap,
 				Optional:    true,
 			},
+			"data_all": DataAllSchema("function"),
 			"body": {
 				Description: "The FQL instructions to be executed.",
 				Type:        schema.TypeString,
@@ -47,6 +55,7 @@ func ResourceFunction() *schema.Resource {
 				Optional:    true,
 				Default:     nil,
 			},
+			"adopt_existing": AdoptExistingSchema("function"),
 			"ts": {
 				Description: "A timestamp of when this function was created.",
 				Type:        schema.TypeInt,
@@ -56,7 +65,7 @@ func ResourceFunction() *schema.Resource {
 	}
 }
 
-func synchroniseFunctionResourceData(res f.Value, data *schema.ResourceData) error {
+func synchroniseFunctionResourceData(res f.Value, data *schema.ResourceData, meta any) error {
 	var obj f.ObjectV
 	if err := res.Get(&obj); err != nil {
 		return err
@@ -66,21 +75,25 @@ func synchroniseFunctionResourceData(res f.Value, data *schema.ResourceData) err
 		data.Set("name", name_)
 	}
 
-	if data_, ok := GetProperty(obj, "data", map[string]any{}); ok {
-		data.Set("data", data_)
-	}
+	data_, _ := GetProperty(obj, "data", map[string]any{})
+	SetData(data, data_, meta)
 
 	if body, ok := GetProperty(obj, "body", ""); ok {
 		data.Set("body", body)
 	}
 
-	if role, ok := GetProperty(obj, "role", ""); ok {
-		data.Set("role", role)
+	// Built-in roles are returned as strings, whereas user-defined roles are returned as references.
+	switch role := obj["role"].(type) {
+	case f.RefV:
+		data.Set("role", role.ID)
+	case f.StringV:
+		data.Set("role", string(role))
+	default:
+		data.Set("role", "")
 	}
 
-	if ttl, ok := GetProperty[any](obj, "ttl", nil); ok {
-		data.Set("ttl", ttl)
-	}
+	ttl, _ := GetProperty[any](obj, "ttl", nil)
+	data.Set("ttl", ttl)
 
 	if ts, ok := GetProperty[int64](obj, "ts", 0); ok {
 		data.SetId(strconv.FormatInt(ts, 10))
@@ -91,17 +104,17 @@ func synchroniseFunctionResourceData(res f.Value, data *schema.ResourceData) err
 }
 
 func resourceFunctionCreate(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
 	name := data.Get("name").(string)
 
-	if err := CheckNameNotBlacklisted(name, "function"); err != nil {
+	if err := CheckName(name, "function"); err != nil {
 		return diag.FromErr(err)
 	}
 
 	obj := f.Obj{
 		"name": name,
-		"data": data.Get("data"),
+		"data": data.Get("data_all"),
 		"body": data.Get("body"),
 		"ttl":  data.Get("ttl"),
 	}
@@ -110,29 +123,46 @@ func resourceFunctionCreate(ctx context.Context, data *schema.ResourceData, meta
 		obj["role"] = f.Role(role)
 	}
 
-	res, err := conn.Query(f.CreateFunction(obj))
+	var diags diag.Diagnostics
+
+	res, err := createObject(ctx, conn, data, f.Function(name), f.CreateFunction(obj))
+	if shouldAdopt(data, conn, err) {
+		// The role is only sent when set, so an existing role is removed when adopting.
+		res, err = adopt(ctx, conn, f.Function(name), data, obj, append([]string{"role"}, functionNullableProperties...))
+		diags = append(diags, AdoptionWarning("fauna_function", name))
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return QueryDiagnostics(ctx, err, ResourceFunction())
 	}
 
-	if err := synchroniseFunctionResourceData(res, data); err != nil {
+	if err := synchroniseFunctionResourceData(res, data, meta); err != nil {
 		return diag.FromErr(err)
 	}
 
-	return resourceFunctionRead(ctx, data, meta)
+	if err := waitForDefinition(ctx, conn, data, f.Function(name), int64(data.Get("ts").(int))); err != nil {
+		return QueryDiagnostics(ctx, err, ResourceFunction())
+	}
+
+	return append(diags, resourceFunctionRead(ctx, data, meta)...)
 }
 
 func resourceFunctionRead(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	res, err := conn.Query(f.Get(f.Function(data.Get("name"))))
+	res, err := conn.Query(ctx, f.Get(f.Function(data.Get("name"))))
+	if IsNotFound(err) && !data.IsNewResource() {
+		// The function was deleted outside of Terraform, or along with its parent.
+		tflog.Warn(ctx, "Function no longer exists, removing it from the state", map[string]any{"name": data.Get("name")})
+		data.SetId("")
+		return diags
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return QueryDiagnostics(ctx, err, ResourceFunction())
 	}
 
-	if err := synchroniseFunctionResourceData(res, data); err != nil {
+	if err := synchroniseFunctionResourceData(res, data, meta); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -140,27 +170,34 @@ func resourceFunctionRead(ctx context.Context, data *schema.ResourceData, meta a
 }
 
 var functionPropertiesToCheck = []string{"name", "data", "body", "ttl"}
+var functionNullableProperties = []string{"ttl"}
 
 func resourceFunctionUpdate(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	object := make(map[string]any)
-	for _, property := range functionPropertiesToCheck {
-		if !data.HasChange(property) {
-			continue
+	if data.HasChange("name") {
+		if err := CheckName(data.Get("name").(string), "function"); err != nil {
+			return diag.FromErr(err)
 		}
-
-		object[property] = data.Get(property)
 	}
 
+	object := GetChangedProperties(data, functionPropertiesToCheck, functionNullableProperties)
+
 	if data.HasChange("role") {
-		object["role"] = f.Role(data.Get("role"))
+		if role := data.Get("role"); role != "" {
+			object["role"] = f.Role(role)
+		} else {
+			object["role"] = f.Null()
+		}
 	}
 
 	if len(object) != 0 {
-		_, err := conn.Query(f.Update(f.Function(data.Get("name")), object))
+		// If this function is being renamed, it can only be referred to by its previous name.
+		previousName, _ := data.GetChange("name")
+
+		_, err := conn.Query(ctx, f.Update(f.Function(previousName), object))
 		if err != nil {
-			return diag.FromErr(err)
+			return QueryDiagnostics(ctx, err, ResourceFunction())
 		}
 	}
 
@@ -170,11 +207,12 @@ func resourceFunctionUpdate(ctx context.Context, data *schema.ResourceData, meta
 func resourceFunctionDelete(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	_, err := conn.Query(f.Delete(f.Function(data.Get("name"))))
-	if err != nil {
-		return diag.FromErr(err)
+	// A function that no longer exists has been destroyed already.
+	_, err := conn.Query(ctx, f.Delete(f.Function(data.Get("name"))))
+	if err != nil && !IsNotFound(err) {
+		return QueryDiagnostics(ctx, err, ResourceFunction())
 	}
 
 	data.SetId("")
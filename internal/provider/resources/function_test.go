@@ -36,6 +36,18 @@ func TestAccFunction(t *testing.T) {
 					resource.TestCheckResourceAttr("fauna_function.function", "data.sample_key_2", "false"),
 					resource.TestCheckResourceAttr("fauna_function.function", "data.sample_key_3", "65"),
 					resource.TestCheckResourceAttr("fauna_function.function", "ttl", "7"),
+					resource.TestCheckResourceAttr("fauna_function.function", "role", "server"),
+				),
+			},
+			{
+				Config: testAccFunctionConfiguration_removedProperties(rColName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFunctionExists("fauna_function.function"),
+					resource.TestCheckResourceAttr("fauna_function.function", "data.%", "1"),
+					resource.TestCheckResourceAttr("fauna_function.function", "data.sample_key", "sample_value"),
+					resource.TestCheckNoResourceAttr("fauna_function.function", "data.sample_key_2"),
+					resource.TestCheckNoResourceAttr("fauna_function.function", "data.sample_key_3"),
+					acctest.TestAccCheckPropertiesRemoved("fauna_function.function", f.Function, "data.sample_key_2", "data.sample_key_3", "ttl", "role"),
 				),
 			},
 		},
@@ -61,6 +73,18 @@ resource "fauna_function" "function" {
 	}
 	body = "Query(Lambda(\"X\", Paginate(Collections())))"
 	ttl = 7
+	role = "server"
+}`, rColName)
+}
+
+func testAccFunctionConfiguration_removedProperties(rColName string) string {
+	return fmt.Sprintf(`
+resource "fauna_function" "function" {
+	name         = "%s"
+	data = {
+		sample_key = "sample_value"
+	}
+	body = "Query(Lambda(\"X\", Paginate(Collections())))"
 }`, rColName)
 }
 
@@ -80,7 +104,7 @@ func testAccCheckFunctionExists(resourceName string) resource.TestCheckFunc {
 			}
 		}
 
-		client := acctest.TestAccProvider.Meta().(*f.FaunaClient)
+		client := acctest.TestAccClient()
 
 		if _, err := client.Query(f.Get(f.Function(name))); err != nil {
 			return err
@@ -91,7 +115,7 @@ func testAccCheckFunctionExists(resourceName string) resource.TestCheckFunc {
 }
 
 func testAccCheckFunctionDestroy(s *terraform.State) error {
-	client := acctest.TestAccProvider.Meta().(*f.FaunaClient)
+	client := acctest.TestAccClient()
 
 	for _, res := range s.RootModule().Resources {
 		if res.Type != "fauna_function" {
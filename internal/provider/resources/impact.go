@@ -0,0 +1,181 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+// The number of documents counted at most when estimating the impact of a change, which keeps
+// the cost of planning bounded for large collections.
+const impactDocumentLimit = 10000
+
+// PlannedImpactSchema returns the schema of the attribute through which the impact of the changes
+// planned to a resource of type `resourceType` is shown in the plan.
+func PlannedImpactSchema(resourceType string, impact string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("The impact of the changes planned to this %s, estimated when planning them, such as %s. The plugin SDK cannot attach warnings to plans, so it is shown in the plan as a change of this attribute for reviewers to see before applying. It keeps the estimate of the last applied change until other changes are planned.", resourceType, impact),
+		Type:        schema.TypeString,
+		Computed:    true,
+	}
+}
+
+// customizeIndexImpact shows the cost of building an index, which is created or replaced over the
+// documents of its source collection, in the plan.
+func customizeIndexImpact(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	conn, ok := meta.(*client.Client)
+	if !ok || !diff.NewValueKnown("name") || !diff.NewValueKnown("source") {
+		return nil
+	}
+
+	name := diff.Get("name").(string)
+	source := diff.Get("source").(string)
+
+	var impact string
+	switch {
+	case diff.Id() == "":
+		impact = fmt.Sprintf("Creating the index '%s' builds it over %%s of the collection '%s'.", name, source)
+	case !diff.HasChanges(indexDefinitionProperties...):
+		return setPlannedImpact(diff, "")
+	case diff.Get("replace_strategy").(string) == ReplaceStrategyShadow:
+		impact = fmt.Sprintf("Replacing the index '%s' builds a shadow index over %%s of the collection '%s'.", name, source)
+	default:
+		impact = fmt.Sprintf("Replacing the index '%s' deletes it and rebuilds it over %%s of the collection '%s'. No index exists under its name until the rebuild finishes.", name, source)
+	}
+
+	count, ok := countImpactedDocuments(ctx, conn, "fauna_index", name, source)
+	if !ok {
+		return setPlannedImpact(diff, "")
+	}
+
+	return setPlannedImpact(diff, fmt.Sprintf(impact, DescribeDocumentCount(count)))
+}
+
+// customizeCollectionImpact shows the documents and history that a collection irreversibly loses
+// when its retention is lowered in the plan.
+func customizeCollectionImpact(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	conn, ok := meta.(*client.Client)
+	if !ok || diff.Id() == "" || !diff.NewValueKnown("name") {
+		return nil
+	}
+
+	previousName, _ := diff.GetChange("name")
+	name := previousName.(string)
+
+	previousHistoryDays, historyDays := diff.GetChange("history_days")
+	previousTTLDays, ttlDays := diff.GetChange("ttl_days")
+
+	impacts := collectionRetentionImpacts(name, previousHistoryDays.(int), historyDays.(int), previousTTLDays.(int), ttlDays.(int))
+	if len(impacts) == 0 {
+		return setPlannedImpact(diff, "")
+	}
+
+	count, ok := countImpactedDocuments(ctx, conn, "fauna_collection", name, name)
+	if !ok {
+		return setPlannedImpact(diff, "")
+	}
+
+	for i, impact := range impacts {
+		impacts[i] = fmt.Sprintf(impact, DescribeDocumentCount(count))
+	}
+
+	return setPlannedImpact(diff, strings.Join(impacts, " "))
+}
+
+// collectionRetentionImpacts describes the documents and history that the collection `name` loses
+// when its `history_days` and `ttl_days` change. Each description holds a `%s` verb for the number
+// of documents of the collection.
+func collectionRetentionImpacts(name string, previousHistoryDays int, historyDays int, previousTTLDays int, ttlDays int) []string {
+	var impacts []string
+
+	if historyDays < previousHistoryDays {
+		impacts = append(impacts, fmt.Sprintf("Lowering `history_days` of the collection '%s' from %d to %d irreversibly removes the history older than %d days of %%s.", name, previousHistoryDays, historyDays, historyDays))
+	}
+
+	if ttlDays != 0 && (previousTTLDays == 0 || ttlDays < previousTTLDays) {
+		impacts = append(impacts, fmt.Sprintf("Setting `ttl_days` of the collection '%s' to %d irreversibly removes those of %%s that are older than %d days.", name, ttlDays, ttlDays))
+	}
+
+	return impacts
+}
+
+// setPlannedImpact shows `impact` in the plan through the `planned_impact` attribute. Without an
+// impact, the estimate of the last applied change is only cleared along with other changes, so
+// that it does not make a plan of its own.
+func setPlannedImpact(diff *schema.ResourceDiff, impact string) error {
+	if diff.Id() != "" && impact == diff.Get("planned_impact").(string) {
+		return nil
+	}
+
+	if diff.Id() != "" && impact == "" && len(diff.GetChangedKeysPrefix("")) == 0 {
+		return nil
+	}
+
+	return diff.SetNew("planned_impact", impact)
+}
+
+// appendPlannedImpact adds `impact` to the impact shown in the plan by the CustomizeDiff functions
+// that ran before, if any.
+func appendPlannedImpact(diff *schema.ResourceDiff, impact string) error {
+	if diff.HasChange("planned_impact") {
+		if planned := diff.Get("planned_impact").(string); planned != "" {
+			impact = planned + " " + impact
+		}
+	}
+
+	return diff.SetNew("planned_impact", impact)
+}
+
+// impactDiagnostics warns of the documents of the collection `collection` that a change which
+// could not be planned, such as destroying it, removed. Changes to an empty collection, or one
+// whose documents cannot be counted, have no impact to report.
+func impactDiagnostics(ctx context.Context, conn *client.Client, resourceType string, name string, collection string, summary string, impact string) diag.Diagnostics {
+	count, ok := countImpactedDocuments(ctx, conn, resourceType, name, collection)
+	if !ok || count == 0 {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  summary,
+		Detail:   fmt.Sprintf(impact, DescribeDocumentCount(count)),
+	}}
+}
+
+// countImpactedDocuments counts the documents of the collection `collection`, up to
+// `impactDocumentLimit`. The impact of a change is only an estimate, so failing to count them,
+// such as when the collection is yet to be created, does not fail the plan.
+func countImpactedDocuments(ctx context.Context, conn *client.Client, resourceType string, name string, collection string) (int64, bool) {
+	ctx = client.WithRequest(ctx, client.Request{ResourceType: resourceType, ObjectName: name, Operation: client.OperationRead})
+
+	res, err := conn.Query(ctx, f.Count(f.Select("data", f.Paginate(f.Documents(f.Collection(collection)), f.Size(impactDocumentLimit+1)))))
+	if err != nil {
+		if !IsNotFound(err) {
+			tflog.Debug(ctx, "Failed to count the documents of the collection", map[string]any{"collection": collection, "error": RedactSecrets(err.Error())})
+		}
+
+		return 0, false
+	}
+
+	return ParseFaunaValue[int64](res), true
+}
+
+// DescribeDocumentCount describes a number of documents counted up to `impactDocumentLimit`.
+func DescribeDocumentCount(count int64) string {
+	switch {
+	case count > impactDocumentLimit:
+		return fmt.Sprintf("more than %d documents", impactDocumentLimit)
+	case count == 1:
+		return "1 document"
+	default:
+		return fmt.Sprintf("%d documents", count)
+	}
+}
@@ -0,0 +1,216 @@
+package resources_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider/resources"
+)
+
+func TestDescribeDocumentCount(t *testing.T) {
+	cases := map[int64]string{
+		0:     "0 documents",
+		1:     "1 document",
+		250:   "250 documents",
+		10000: "10000 documents",
+		10001: "more than 10000 documents",
+	}
+
+	for count, expected := range cases {
+		if description := resources.DescribeDocumentCount(count); description != expected {
+			t.Errorf("Expected %d documents to be described as '%s', but got '%s'.", count, expected, description)
+		}
+	}
+}
+
+func TestCustomizeIndexImpact(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "users_by_email",
+		Attributes: map[string]string{
+			"id":               "users_by_email",
+			"name":             "users_by_email",
+			"source":           "users",
+			"unique":           "false",
+			"serialized":       "true",
+			"replace_strategy": "shadow",
+			"terms.#":          "0",
+			"values.#":         "0",
+			"data.%":           "0",
+			"data_all.%":       "0",
+			"planned_impact":   "Creating the index 'users_by_email' builds it over 1000 documents of the collection 'users'.",
+		},
+	}
+
+	cases := map[string]struct {
+		state    *terraform.InstanceState
+		config   map[string]any
+		expected *string
+	}{
+		"create": {
+			config:   map[string]any{"name": "users_by_email", "source": "users"},
+			expected: impact("Creating the index 'users_by_email' builds it over 1234 documents of the collection 'users'."),
+		},
+		"replace with a shadow index": {
+			state:    state,
+			config:   map[string]any{"name": "users_by_email", "source": "accounts", "replace_strategy": "shadow"},
+			expected: impact("Replacing the index 'users_by_email' builds a shadow index over 1234 documents of the collection 'accounts'."),
+		},
+		"replace": {
+			state:    state,
+			config:   map[string]any{"name": "users_by_email", "source": "accounts"},
+			expected: impact("Replacing the index 'users_by_email' deletes it and rebuilds it over 1234 documents of the collection 'accounts'. No index exists under its name until the rebuild finishes."),
+		},
+		// The estimate of the last applied change is cleared along with other changes.
+		"unchanged definition": {
+			state:    state,
+			config:   map[string]any{"name": "users_by_email", "source": "users", "replace_strategy": "shadow", "serialized": false},
+			expected: impact(""),
+		},
+		// But it does not make a plan of its own.
+		"unchanged": {
+			state:  state,
+			config: map[string]any{"name": "users_by_email", "source": "users", "replace_strategy": "shadow"},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			planned, queries := planImpact(t, resources.ResourceIndex(), c.state, c.config)
+			checkPlannedImpact(t, planned, queries, c.expected)
+		})
+	}
+}
+
+func TestCustomizeCollectionImpact(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "users",
+		Attributes: map[string]string{
+			"id":                  "users",
+			"name":                "users",
+			"history_days":        "30",
+			"ttl_days":            "90",
+			"deletion_protection": "false",
+			"data.%":              "0",
+			"data_all.%":          "0",
+			"planned_impact":      "",
+		},
+	}
+
+	cases := map[string]struct {
+		config   map[string]any
+		expected *string
+	}{
+		"lower retention": {
+			config:   map[string]any{"name": "users", "history_days": 7, "ttl_days": 30},
+			expected: impact("Lowering `history_days` of the collection 'users' from 30 to 7 irreversibly removes the history older than 7 days of 1234 documents. Setting `ttl_days` of the collection 'users' to 30 irreversibly removes those of 1234 documents that are older than 30 days."),
+		},
+		"raise retention": {
+			config: map[string]any{"name": "users", "history_days": 60, "ttl_days": 180},
+		},
+		"unchanged retention": {
+			config: map[string]any{"name": "users", "history_days": 30, "ttl_days": 90, "deletion_protection": true},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			planned, queries := planImpact(t, resources.ResourceCollection(), state, c.config)
+			checkPlannedImpact(t, planned, queries, c.expected)
+		})
+	}
+}
+
+func impact(impact string) *string {
+	return &impact
+}
+
+// planImpact plans `config` against `state` with a server from `impactServer`, and returns the
+// planned value of `planned_impact`, if it changes, along with the number of queries issued.
+func planImpact(t *testing.T, resource *schema.Resource, state *terraform.InstanceState, config map[string]any) (*string, int) {
+	conn, queries, stop := impactServer()
+	defer stop()
+
+	// Terraform plans through SimpleDiff, which customizes the diff once even when replacing.
+	diff, err := resource.SimpleDiff(context.Background(), state, terraform.NewResourceConfigRaw(config), conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attribute, ok := diff.Attributes["planned_impact"]
+	if !ok {
+		return nil, *queries
+	}
+
+	return &attribute.New, *queries
+}
+
+func checkPlannedImpact(t *testing.T, planned *string, queries int, expected *string) {
+	if expected == nil || *expected == "" {
+		if queries != 0 {
+			t.Errorf("Expected no documents to be counted, but %d queries were issued.", queries)
+		}
+	}
+
+	switch {
+	case expected == nil && planned != nil:
+		t.Errorf("Expected `planned_impact` to be left unchanged, but got '%s'.", *planned)
+	case expected != nil && planned == nil:
+		t.Errorf("Expected `planned_impact` to be '%s', but it is left unchanged.", *expected)
+	case expected != nil && *planned != *expected:
+		t.Errorf("Expected `planned_impact` to be '%s', but got '%s'.", *expected, *planned)
+	}
+}
+
+func TestResourceCollectionDelete_impact(t *testing.T) {
+	conn, _, stop := impactServer()
+	defer stop()
+
+	resource := resources.ResourceCollection()
+	data := schema.TestResourceDataRaw(t, resource.Schema, map[string]any{"name": "users"})
+	data.SetId("users")
+
+	diags := resource.DeleteContext(context.Background(), data, conn)
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("Expected a warning, but got %v.", diags)
+	}
+
+	if expected := "The collection 'users' was deleted along with 1234 documents."; diags[0].Detail != expected {
+		t.Errorf("Expected the warning '%s', but got '%s'.", expected, diags[0].Detail)
+	}
+}
+
+// impactServer returns a client of a Fauna server whose collections all hold 1234 documents,
+// along with the number of queries it answered.
+func impactServer() (*client.Client, *int, func()) {
+	queries := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		w.Write([]byte(`{"resource": 1234}`))
+	}))
+
+	return client.New(client.Config{Secret: "secret", Endpoint: server.URL, HTTP: server.Client()}), &queries, server.Close
+}
+
+func checkImpactWarnings(t *testing.T, warnings []string, queries int, expected []string) {
+	if len(expected) == 0 && queries != 0 {
+		t.Errorf("Expected no documents to be counted, but %d queries were issued.", queries)
+	}
+
+	if len(warnings) != len(expected) {
+		t.Fatalf("Expected the warnings %q, but got %q.", expected, warnings)
+	}
+
+	for i := range expected {
+		if warnings[i] != expected[i] {
+			t.Errorf("Expected the warning '%s', but got '%s'.", expected[i], warnings[i])
+		}
+	}
+}
@@ -4,43 +4,53 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
 )
 
 func ResourceIndex() *schema.Resource {
 	return &schema.Resource{
-		CreateContext: resourceIndexCreate,
-		ReadContext:   resourceIndexRead,
-		UpdateContext: resourceIndexUpdate,
-		DeleteContext: resourceIndexDelete,
+		CreateContext: withOperation("fauna_index", client.OperationCreate, resourceIndexCreate),
+		ReadContext:   withOperation("fauna_index", client.OperationRead, resourceIndexRead),
+		UpdateContext: withOperation("fauna_index", client.OperationUpdate, resourceIndexUpdate),
+		DeleteContext: withOperation("fauna_index", client.OperationDelete, resourceIndexDelete),
+
+		CustomizeDiff: customizeDiff("fauna_index", customizeIndexReplacement, customizeIndexImpact, customizeIndexUniqueness),
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultCreateTimeout),
+			Update: schema.DefaultTimeout(defaultIndexUpdateTimeout),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Description: fmt.Sprintf("The name of this index. Cannot be one of: %s.", strings.Join(BlacklistedResourceNames, ", ")),
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      fmt.Sprintf("The name of this index. %s", DescribeNameRules("index")),
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: ValidateName("index"),
 			},
 			"data": {
 				Description: "Developer-defined metadata for this index.",
 				Type:        schema.TypeMap,
 				Optional:    true,
 			},
+			"data_all": DataAllSchema("index"),
 			"source": {
 				Description: "The source collection.",
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
 			},
 			"terms": {
 				Description: "The document fields whose values can be matched for the search term.",
 				Type:        schema.TypeList,
 				Optional:    true,
-				ForceNew:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"field": {
@@ -58,7 +68,6 @@ func ResourceIndex() *schema.Resource {
 				Description: "The document fields whose values are to be returned.",
 				Type:        schema.TypeList,
 				Optional:    true,
-				ForceNew:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"field": {
@@ -79,7 +88,7 @@ func ResourceIndex() *schema.Resource {
 				},
 			},
 			"unique": {
-				Description: "Whether to maintain a `unique` constraint on combined `terms` and `values`.",
+				Description: "Whether to maintain a `unique` constraint on combined `terms` and `values`. When an index is created unique, made unique, or has its definition changed while unique, the first 1000 documents of the source collection are checked, and the plan fails if several of them share the same `terms` and `values`, listing some of them. Duplicates among the other documents only fail the build of the index when applied, so `planned_impact` states when the collection was only checked in part.",
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
@@ -96,6 +105,15 @@ func ResourceIndex() *schema.Resource {
 				Optional:    true,
 				Default:     nil,
 			},
+			"replace_strategy": {
+				Description:      fmt.Sprintf("How this index is replaced when its `source`, `terms` or `values` change, which Fauna cannot update. With `%s`, it is deleted and created again, so no index exists under its name until the new one is built. With `%s`, the new index is built under a temporary name, then swapped in atomically once active, and the old index is deleted; this is bounded by the `update` timeout. Either way, the plan shows the number of documents the index is built over in `planned_impact`. Defaults to `%s`.", ReplaceStrategyRecreate, ReplaceStrategyShadow, ReplaceStrategyRecreate),
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          ReplaceStrategyRecreate,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(ReplaceStrategies, false)),
+			},
+			"adopt_existing": AdoptExistingSchema("index"),
+			"planned_impact": PlannedImpactSchema("index", "the number of documents it is built over when created or replaced"),
 			"ts": {
 				Description: "A timestamp of when this index was created.",
 				Type:        schema.TypeInt,
@@ -105,7 +123,7 @@ func ResourceIndex() *schema.Resource {
 	}
 }
 
-func synchroniseIndexResourceData(res f.Value, data *schema.ResourceData) error {
+func synchroniseIndexResourceData(res f.Value, data *schema.ResourceData, meta any) error {
 	var obj f.ObjectV
 	if err := res.Get(&obj); err != nil {
 		return err
@@ -115,9 +133,8 @@ func synchroniseIndexResourceData(res f.Value, data *schema.ResourceData) error
 		data.Set("name", name_)
 	}
 
-	if data_, ok := GetProperty(obj, "data", map[string]any{}); ok {
-		data.Set("data", data_)
-	}
+	data_, _ := GetProperty(obj, "data", map[string]any{})
+	SetData(data, data_, meta)
 
 	if source, ok := GetProperty(obj, "source", f.RefV{}); ok {
 		var source_ f.RefV
@@ -142,9 +159,8 @@ func synchroniseIndexResourceData(res f.Value, data *schema.ResourceData) error
 		data.Set("serialized", serialized)
 	}
 
-	if ttl, ok := GetProperty[any](obj, "ttl", nil); ok {
-		data.Set("ttl", ttl)
-	}
+	ttl, _ := GetProperty[any](obj, "ttl", nil)
+	data.Set("ttl", ttl)
 
 	if ts, ok := GetProperty[int64](obj, "ts", 0); ok {
 		data.SetId(strconv.FormatInt(ts, 10))
@@ -155,46 +171,72 @@ func synchroniseIndexResourceData(res f.Value, data *schema.ResourceData) error
 }
 
 func resourceIndexCreate(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
 	name := data.Get("name").(string)
 
-	if err := CheckNameNotBlacklisted(name, "index"); err != nil {
+	if err := CheckName(name, "index"); err != nil {
 		return diag.FromErr(err)
 	}
 
-	res, err := conn.Query(f.CreateIndex(f.Obj{
+	var diags diag.Diagnostics
+
+	// The source, terms and values of an existing index cannot be updated, so they are left out
+	// when adopting one, and replaced by a later apply if they differ.
+	obj := f.Obj{
 		"name":       name,
-		"data":       data.Get("data"),
-		"source":     f.Collection(data.Get("source")),
-		"terms":      data.Get("terms"),
-		"values":     data.Get("values"),
+		"data":       data.Get("data_all"),
 		"unique":     data.Get("unique"),
 		"serialized": data.Get("serialized"),
 		"ttl":        data.Get("ttl"),
-	}))
+	}
+
+	create := f.Obj{
+		"source": f.Collection(data.Get("source")),
+		"terms":  data.Get("terms"),
+		"values": data.Get("values"),
+	}
+	for key, value := range obj {
+		create[key] = value
+	}
+
+	res, err := createObject(ctx, conn, data, f.Index(name), f.CreateIndex(create))
+	if shouldAdopt(data, conn, err) {
+		res, err = adopt(ctx, conn, f.Index(name), data, obj, indexNullableProperties)
+		diags = append(diags, AdoptionWarning("fauna_index", name))
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return QueryDiagnostics(ctx, err, ResourceIndex())
 	}
 
-	if err := synchroniseIndexResourceData(res, data); err != nil {
+	if err := synchroniseIndexResourceData(res, data, meta); err != nil {
 		return diag.FromErr(err)
 	}
 
-	return resourceIndexRead(ctx, data, meta)
+	if err := waitForDefinition(ctx, conn, data, f.Index(name), int64(data.Get("ts").(int))); err != nil {
+		return QueryDiagnostics(ctx, err, ResourceIndex())
+	}
+
+	return append(diags, resourceIndexRead(ctx, data, meta)...)
 }
 
 func resourceIndexRead(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	res, err := conn.Query(f.Get(f.Index(data.Get("name"))))
+	res, err := conn.Query(ctx, f.Get(f.Index(data.Get("name"))))
+	if IsNotFound(err) && !data.IsNewResource() {
+		// The index was deleted outside of Terraform, or along with its parent.
+		tflog.Warn(ctx, "Index no longer exists, removing it from the state", map[string]any{"name": data.Get("name")})
+		data.SetId("")
+		return diags
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return QueryDiagnostics(ctx, err, ResourceIndex())
 	}
 
-	if err := synchroniseIndexResourceData(res, data); err != nil {
+	if err := synchroniseIndexResourceData(res, data, meta); err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -202,23 +244,35 @@ func resourceIndexRead(ctx context.Context, data *schema.ResourceData, meta any)
 }
 
 var indexPropertiesToCheck = []string{"name", "data", "terms", "values", "unique", "serialized", "ttl", "ts"}
+var indexNullableProperties = []string{"ttl"}
 
 func resourceIndexUpdate(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
-	conn := meta.(*f.FaunaClient)
+	conn := meta.(*client.Client)
 
-	object := make(map[string]any)
-	for _, property := range indexPropertiesToCheck {
-		if !data.HasChange(property) {
-			continue
+	if data.HasChange("name") {
+		if err := CheckName(data.Get("name").(string), "index"); err != nil {
+			return diag.FromErr(err)
 		}
+	}
 
-		object[property] = data.Get(property)
+	if data.HasChanges(indexDefinitionProperties...) {
+		diags := replaceIndexWithShadow(ctx, conn, data)
+		if diags.HasError() {
+			return diags
+		}
+
+		return append(diags, resourceIndexRead(ctx, data, meta)...)
 	}
 
+	object := GetChangedProperties(data, indexPropertiesToCheck, indexNullableProperties)
+
 	if len(object) != 0 {
-		_, err := conn.Query(f.Update(f.Index(data.Get("name")), object))
+		// If this index is being renamed, it can only be referred to by its previous name.
+		previousName, _ := data.GetChange("name")
+
+		_, err := conn.Query(ctx, f.Update(f.Index(previousName), object))
 		if err != nil {
-			return diag.FromErr(err)
+			return QueryDiagnostics(ctx, err, ResourceIndex())
 		}
 	}
 
@@ -228,11 +282,20 @@ func resourceIndexUpdate(ctx context.Context, data *schema.ResourceData, meta an
 func resourceIndexDelete(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	conn := meta.(*f.FaunaClient)
-
-	_, err := conn.Query(f.Delete(f.Index(data.Get("name"))))
-	if err != nil {
-		return diag.FromErr(err)
+	conn := meta.(*client.Client)
+
+	_, err := conn.Query(ctx, f.Delete(f.Index(data.Get("name"))))
+	if IsNotFound(err) {
+		// Deleting a collection also deletes its indexes, so the index is usually gone because its
+		// source collection was destroyed first.
+		res, sourceErr := conn.Query(ctx, f.Exists(f.Collection(data.Get("source"))))
+		if sourceErr == nil && !ParseFaunaValue[bool](res) {
+			tflog.Info(ctx, "Index was deleted along with its source collection", map[string]any{"name": data.Get("name"), "source": data.Get("source")})
+		} else {
+			tflog.Info(ctx, "Index was already deleted", map[string]any{"name": data.Get("name")})
+		}
+	} else if err != nil {
+		return QueryDiagnostics(ctx, err, ResourceIndex())
 	}
 
 	data.SetId("")
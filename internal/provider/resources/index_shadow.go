@@ -0,0 +1,155 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+// How an index is replaced when its definition changes.
+const (
+	// The index is deleted, then created again with the new definition.
+	ReplaceStrategyRecreate = "recreate"
+	// An index with the new definition is built under a temporary name, then swapped in.
+	ReplaceStrategyShadow = "shadow"
+)
+
+var ReplaceStrategies = []string{ReplaceStrategyRecreate, ReplaceStrategyShadow}
+
+// The attributes of an index that Fauna cannot update.
+var indexDefinitionProperties = []string{"source", "terms", "values"}
+
+// How long an index may take to build when it is replaced by default.
+const defaultIndexUpdateTimeout = 30 * time.Minute
+
+// customizeIndexReplacement plans the replacement of an index whose definition changes, unless
+// it is replaced through a shadow index when applied.
+func customizeIndexReplacement(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	if !indexReplacementPlanned(diff) {
+		return nil
+	}
+
+	for _, property := range indexDefinitionProperties {
+		if diff.HasChange(property) {
+			if err := diff.ForceNew(property); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// indexReplacementPlanned reports whether the existing index is deleted and created again, because
+// its definition changes and it is not replaced through a shadow index.
+func indexReplacementPlanned(diff *schema.ResourceDiff) bool {
+	return diff.Id() != "" && diff.Get("replace_strategy").(string) != ReplaceStrategyShadow && diff.HasChanges(indexDefinitionProperties...)
+}
+
+// replaceIndexWithShadow replaces an index whose definition changed without a period where no
+// index exists under its name. An index with the new definition is built under a temporary name,
+// and once it is active, the old index is renamed away and the new one renamed into place in a
+// single transaction, before the old index is deleted.
+func replaceIndexWithShadow(ctx context.Context, conn *client.Client, data *schema.ResourceData) diag.Diagnostics {
+	previousName, _ := data.GetChange("name")
+	name := data.Get("name").(string)
+
+	suffix := time.Now().UTC().Format("20060102150405")
+	shadowName := shadowIndexName(name, "shadow", suffix)
+	retiredName := shadowIndexName(previousName.(string), "retired", suffix)
+
+	obj := f.Obj{
+		"name":       shadowName,
+		"data":       data.Get("data_all"),
+		"source":     f.Collection(data.Get("source")),
+		"terms":      data.Get("terms"),
+		"values":     data.Get("values"),
+		"unique":     data.Get("unique"),
+		"serialized": data.Get("serialized"),
+	}
+
+	if ttl, ok := data.GetOk("ttl"); ok {
+		obj["ttl"] = ttl
+	}
+
+	if _, err := createObject(ctx, conn, data, f.Index(shadowName), f.CreateIndex(obj)); err != nil {
+		return QueryDiagnostics(ctx, err, ResourceIndex())
+	}
+
+	tflog.Info(ctx, "Building shadow index", map[string]any{"name": name, "shadow": shadowName})
+
+	if err := waitForIndexActive(ctx, conn, data, shadowName); err != nil {
+		// The old index is left untouched, so the shadow index is only clutter.
+		if _, deleteErr := conn.Query(ctx, f.Delete(f.Index(shadowName))); deleteErr != nil {
+			tflog.Warn(ctx, "Failed to delete the shadow index", map[string]any{"shadow": shadowName, "error": deleteErr.Error()})
+		}
+
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Shadow index did not become active",
+			Detail:   fmt.Sprintf("The shadow index '%s' replacing the index '%s' did not finish building: %s. The index '%s' was left unchanged. Increase the `update` timeout of the resource for indexes over large collections.", shadowName, previousName, RedactSecrets(err.Error()), previousName),
+		}}
+	}
+
+	res, err := conn.Query(ctx, f.Do(
+		f.Update(f.Index(previousName), f.Obj{"name": retiredName}),
+		f.Update(f.Index(shadowName), f.Obj{"name": name}),
+	))
+	if err != nil {
+		return QueryDiagnostics(ctx, err, ResourceIndex())
+	}
+
+	// Fauna's schema cache may still hold the old index under the name the shadow index took.
+	var ts int64
+	res.At(f.ObjKey("ts")).Get(&ts)
+
+	if err := waitForDefinition(ctx, conn, data, f.Index(name), ts); err != nil {
+		return QueryDiagnostics(ctx, err, ResourceIndex())
+	}
+
+	if _, err := conn.Query(ctx, f.Delete(f.Index(retiredName))); err != nil && !IsNotFound(err) {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Failed to delete the replaced index",
+			Detail:   fmt.Sprintf("The index '%s' was replaced, but the previous definition, renamed to '%s', could not be deleted: %s. Delete it manually.", name, retiredName, RedactSecrets(err.Error())),
+		}}
+	}
+
+	return nil
+}
+
+// shadowIndexName derives the temporary name of an index from `name`, keeping within the
+// maximum length of names.
+func shadowIndexName(name string, role string, suffix string) string {
+	tail := fmt.Sprintf("_%s_%s", role, suffix)
+	if len(name)+len(tail) > MaximumNameLength {
+		name = name[:MaximumNameLength-len(tail)]
+	}
+
+	return name + tail
+}
+
+// waitForIndexActive waits until the index called `name` has been built.
+func waitForIndexActive(ctx context.Context, conn *client.Client, data *schema.ResourceData, name string) error {
+	return resource.RetryContext(ctx, remainingTime(ctx, data), func() *resource.RetryError {
+		res, err := conn.Query(ctx, f.Select("active", f.Get(f.Index(name))))
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		if !ParseFaunaValue[bool](res) {
+			return resource.RetryableError(fmt.Errorf("The index '%s' is still being built.", name))
+		}
+
+		return nil
+	})
+}
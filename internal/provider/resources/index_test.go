@@ -2,6 +2,7 @@ package resources_test
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -42,12 +43,207 @@ func TestAccIndex(t *testing.T) {
 					resource.TestCheckResourceAttr("fauna_index.index", "unique", "true"),
 					resource.TestCheckResourceAttr("fauna_index.index", "serialized", "true"),
 					resource.TestCheckResourceAttr("fauna_index.index", "ttl", "7"),
+					resource.TestCheckResourceAttr("fauna_index.index", "data.sample_key", "sample_value"),
+				),
+			},
+			{
+				Config: testAccIndexConfiguration_removedProperties(rColName, rIndexName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIndexExists("fauna_index.index"),
+					resource.TestCheckNoResourceAttr("fauna_index.index", "data.sample_key"),
+					acctest.TestAccCheckPropertiesRemoved("fauna_index.index", f.Index, "data.sample_key", "ttl"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccIndex_sourceDeleted(t *testing.T) {
+	rColName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+	rIndexName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acctest.TestAccPreCheck(t) },
+		Providers:    acctest.TestAccProviders,
+		CheckDestroy: testAccCheckIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIndexConfiguration(rColName, rIndexName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIndexExists("fauna_index.index"),
+				),
+			},
+			{
+				// Deleting the collection also deletes the index, so both are recreated.
+				PreConfig: func() {
+					client := acctest.TestAccClient()
+
+					if _, err := client.Query(f.Delete(f.Collection(rColName))); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccIndexConfiguration(rColName, rIndexName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCollectionExists("fauna_collection.collection"),
+					testAccCheckIndexExists("fauna_index.index"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIndex_recreateWithSameName(t *testing.T) {
+	rColName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+	rIndexName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acctest.TestAccPreCheck(t) },
+		Providers:    acctest.TestAccProviders,
+		CheckDestroy: testAccCheckIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIndexConfiguration_term(rColName, rIndexName, "sample_property", "recreate"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIndexExists("fauna_index.index"),
+					resource.TestCheckResourceAttr("fauna_index.index", "terms.0.field.1", "sample_property"),
+				),
+			},
+			{
+				// Changing the terms replaces the index with one with the same name, which is only
+				// possible once Fauna's schema cache has forgotten the deleted index.
+				Config: testAccIndexConfiguration_term(rColName, rIndexName, "different_sample_property", "recreate"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIndexExists("fauna_index.index"),
+					resource.TestCheckResourceAttr("fauna_index.index", "terms.0.field.1", "different_sample_property"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIndex_shadowReplace(t *testing.T) {
+	rColName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+	rIndexName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+
+	var ts string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acctest.TestAccPreCheck(t) },
+		Providers:    acctest.TestAccProviders,
+		CheckDestroy: testAccCheckIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIndexConfiguration_term(rColName, rIndexName, "sample_property", "shadow"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIndexExists("fauna_index.index"),
+					func(s *terraform.State) error {
+						ts = s.RootModule().Resources["fauna_index.index"].Primary.Attributes["ts"]
+						return nil
+					},
+				),
+			},
+			{
+				// The index is updated in place, rather than replaced, by swapping in a shadow index.
+				Config: testAccIndexConfiguration_term(rColName, rIndexName, "different_sample_property", "shadow"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIndexExists("fauna_index.index"),
+					resource.TestCheckResourceAttr("fauna_index.index", "terms.0.field.1", "different_sample_property"),
+					func(s *terraform.State) error {
+						if current := s.RootModule().Resources["fauna_index.index"].Primary.Attributes["ts"]; current == ts {
+							return fmt.Errorf("Expected the shadow index to replace the index, but its timestamp is unchanged.")
+						}
+
+						return testAccCheckIndexCount(rColName, 1)
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccIndex_uniqueDuplicates(t *testing.T) {
+	rColName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+	rIndexName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acctest.TestAccPreCheck(t) },
+		Providers:    acctest.TestAccProviders,
+		CheckDestroy: testAccCheckIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCollectionConfiguration(rColName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckCollectionExists("fauna_collection.collection"),
+				),
+			},
+			{
+				// Two documents share the term of the index, so it cannot be unique.
+				PreConfig: func() {
+					client := acctest.TestAccClient()
+
+					for i := 0; i < 2; i++ {
+						if _, err := client.Query(f.Create(f.Collection(rColName), f.Obj{"data": f.Obj{"sample_property": "duplicate"}})); err != nil {
+							t.Fatal(err)
+						}
+					}
+				},
+				Config:      testAccIndexConfiguration_unique(rColName, rIndexName),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Cannot make the index '" + rIndexName + "' unique"),
+			},
+			{
+				PreConfig: func() {
+					client := acctest.TestAccClient()
+
+					if _, err := client.Query(f.Create(f.Collection(rColName), f.Obj{"data": f.Obj{"sample_property": "distinct"}})); err != nil {
+						t.Fatal(err)
+					}
+
+					if _, err := client.Query(f.Delete(f.Select(f.Arr{"data", 0}, f.Paginate(f.Documents(f.Collection(rColName)), f.Size(1))))); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccIndexConfiguration_unique(rColName, rIndexName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIndexExists("fauna_index.index"),
+					resource.TestCheckResourceAttr("fauna_index.index", "unique", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccIndex_namingRulesOnReplace(t *testing.T) {
+	rColName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+	rIndexName := sdkacctest.RandStringFromCharSet(10, sdkacctest.CharSetAlphaNum)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { acctest.TestAccPreCheck(t) },
+		Providers:    acctest.TestAccProviders,
+		CheckDestroy: testAccCheckIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIndexConfiguration_term(rColName, rIndexName, "sample_property", "recreate"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIndexExists("fauna_index.index"),
+				),
+			},
+			{
+				// Existing names are exempt from new naming rules.
+				Config:   testAccIndexConfiguration_namingRules(testAccIndexConfiguration_term(rColName, rIndexName, "sample_property", "recreate")),
+				PlanOnly: true,
+			},
+			{
+				// Replacing the index would create it again with a name breaking the rules, so the
+				// plan fails rather than the index being deleted when applied.
+				Config:      testAccIndexConfiguration_namingRules(testAccIndexConfiguration_term(rColName, rIndexName, "different_sample_property", "recreate")),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("does not follow the naming rules"),
+			},
+		},
+	})
+}
+
 func testAccIndexConfiguration(rColName string, rIndexName string) string {
 	return fmt.Sprintf(`
 resource "fauna_collection" "collection" {
@@ -75,6 +271,57 @@ resource "fauna_index" "index" {
 `, rColName, rIndexName)
 }
 
+func testAccIndexConfiguration_term(rColName string, rIndexName string, term string, replaceStrategy string) string {
+	return fmt.Sprintf(`
+resource "fauna_collection" "collection" {
+	name = "%[1]s"
+}
+
+resource "fauna_index" "index" {
+	depends_on = [fauna_collection.collection]
+
+	name             = "%[2]s"
+	source           = "%[1]s"
+	replace_strategy = "%[4]s"
+	terms {
+		field = ["data", "%[3]s"]
+	}
+}
+`, rColName, rIndexName, term, replaceStrategy)
+}
+
+func testAccIndexConfiguration_unique(rColName string, rIndexName string) string {
+	return fmt.Sprintf(`
+resource "fauna_collection" "collection" {
+	name = "%[1]s"
+}
+
+resource "fauna_index" "index" {
+	depends_on = [fauna_collection.collection]
+
+	name   = "%[2]s"
+	source = "%[1]s"
+	unique = true
+	terms {
+		field = ["data", "sample_property"]
+	}
+}
+`, rColName, rIndexName)
+}
+
+// testAccIndexConfiguration_namingRules requires the names of indexes in `configuration` to
+// start with `idx_`, which random names do not.
+func testAccIndexConfiguration_namingRules(configuration string) string {
+	return `
+provider "fauna" {
+	naming_rules {
+		resource_type = "fauna_index"
+		prefix        = "idx_"
+	}
+}
+` + configuration
+}
+
 func testAccIndexConfiguration_addedProperties(rColName string, rIndexName string) string {
 	return fmt.Sprintf(`
 resource "fauna_collection" "collection" {
@@ -98,6 +345,9 @@ resource "fauna_index" "index" {
 	values {
 		field = ["data", "different_sample_property"]
 	}
+	data = {
+		sample_key = "sample_value"
+	}
 	unique = true
 	serialized = true
 	ttl = 7
@@ -105,6 +355,60 @@ resource "fauna_index" "index" {
 `, rColName, rIndexName)
 }
 
+func testAccIndexConfiguration_removedProperties(rColName string, rIndexName string) string {
+	return fmt.Sprintf(`
+resource "fauna_collection" "collection" {
+	name = "%[1]s"
+}
+
+resource "fauna_index" "index" {
+	depends_on = [fauna_collection.collection]
+
+	name   = "%[2]s"
+	source = "%[1]s"
+	terms {
+		field = ["data", "sample_property"]
+	}
+	terms {
+		field = ["data", "different_sample_property"]
+	}
+	values {
+		field = ["data", "sample_property"]
+	}
+	values {
+		field = ["data", "different_sample_property"]
+	}
+	unique = true
+	serialized = true
+}
+`, rColName, rIndexName)
+}
+
+// testAccCheckIndexCount checks that the collection called `rColName` is the source of `count`
+// indexes, so that no shadow or retired index is left behind.
+func testAccCheckIndexCount(rColName string, count int) error {
+	client := acctest.TestAccClient()
+
+	res, err := client.Query(f.Count(f.Filter(
+		f.Paginate(f.Indexes(), f.Size(1000)),
+		f.Lambda("index", f.Equals(f.Select("source", f.Get(f.Var("index"))), f.Collection(rColName))),
+	)))
+	if err != nil {
+		return err
+	}
+
+	var indexes []int
+	if err := res.At(f.ObjKey("data")).Get(&indexes); err != nil {
+		return err
+	}
+
+	if len(indexes) != 1 || indexes[0] != count {
+		return fmt.Errorf("Expected the collection '%s' to have %d indexes, but got %v.", rColName, count, indexes)
+	}
+
+	return nil
+}
+
 func testAccCheckIndexExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		var id string
@@ -121,7 +425,7 @@ func testAccCheckIndexExists(resourceName string) resource.TestCheckFunc {
 			}
 		}
 
-		client := acctest.TestAccProvider.Meta().(*f.FaunaClient)
+		client := acctest.TestAccClient()
 
 		_, err := client.Query(f.Get(f.Index(id)))
 		if err != nil {
@@ -133,7 +437,7 @@ func testAccCheckIndexExists(resourceName string) resource.TestCheckFunc {
 }
 
 func testAccCheckIndexDestroy(s *terraform.State) error {
-	client := acctest.TestAccProvider.Meta().(*f.FaunaClient)
+	client := acctest.TestAccClient()
 
 	for _, res := range s.RootModule().Resources {
 		if res.Type != "fauna_index" {
@@ -0,0 +1,247 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+// The number of documents read per query, and at most, when checking a collection for documents
+// that would violate the unique constraint of an index. The check runs on every plan, so it is
+// bounded to a couple of queries.
+const (
+	uniqueScanPageSize = 500
+	uniqueScanLimit    = 1000
+)
+
+// The number of conflicting documents listed at most when a unique constraint would be violated.
+const uniqueConflictSample = 10
+
+// customizeIndexUniqueness fails the plan of a unique index whose source collection already holds
+// documents sharing the same terms and values, which Fauna would only refuse once the index is
+// being built. The collection is checked when the index is created, made unique, or has its
+// definition changed, up to `uniqueScanLimit` documents, and the plan shows when it was not
+// checked in full.
+func customizeIndexUniqueness(ctx context.Context, diff *schema.ResourceDiff, meta any) error {
+	conn, ok := meta.(*client.Client)
+	if !ok || !diff.Get("unique").(bool) {
+		return nil
+	}
+
+	if diff.Id() != "" && !diff.HasChange("unique") && !diff.HasChanges(indexDefinitionProperties...) {
+		return nil
+	}
+
+	for _, property := range append([]string{"name", "unique"}, indexDefinitionProperties...) {
+		if !diff.NewValueKnown(property) {
+			return nil
+		}
+	}
+
+	terms := indexFields(diff.Get("terms"))
+	values := indexFields(diff.Get("values"))
+
+	// Without terms or values, the index holds the ref of every document, which are all distinct.
+	if len(terms) == 0 && len(values) == 0 {
+		return nil
+	}
+
+	name := diff.Get("name").(string)
+	source := diff.Get("source").(string)
+
+	ctx = client.WithRequest(ctx, client.Request{ResourceType: "fauna_index", ObjectName: name, Operation: client.OperationRead})
+
+	conflicts, scanned, complete, err := findUniqueConflicts(ctx, conn, source, terms, values)
+	if err != nil {
+		// The source collection may be yet to be created, in which case it has no documents.
+		if !IsNotFound(err) {
+			tflog.Warn(ctx, "Failed to check the source collection for documents violating the unique constraint", map[string]any{"name": name, "source": source, "error": RedactSecrets(err.Error())})
+		}
+
+		return nil
+	}
+
+	scope := fmt.Sprintf("the collection '%s'", source)
+	if !complete {
+		scope = fmt.Sprintf("the first %d documents of the collection '%s'", scanned, source)
+	}
+
+	if len(conflicts) != 0 {
+		return fmt.Errorf("Cannot make the index '%s' unique: %d combinations of its `terms` and `values` are shared by several documents among %s. Remove the duplicates before applying, or set `unique = false`. Conflicting documents:\n%s", name, len(conflicts), scope, describeUniqueConflicts(source, conflicts))
+	}
+
+	// Duplicates among the documents that were not checked only fail the build of the index, so
+	// the plan shows that the check was partial.
+	if !complete {
+		return appendPlannedImpact(diff, fmt.Sprintf("Only %s were checked for documents sharing the unique `terms` and `values` of the index '%s'. Duplicates among the others fail the build of the index.", scope, name))
+	}
+
+	return nil
+}
+
+// indexFields returns the field paths of the `terms` or `values` of an index.
+func indexFields(entries any) [][]string {
+	var fields [][]string
+	for _, entry := range entries.([]any) {
+		var field []string
+		for _, segment := range entry.(map[string]any)["field"].([]any) {
+			field = append(field, segment.(string))
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// findUniqueConflicts reads the documents of the collection `source`, up to `uniqueScanLimit`,
+// and groups the refs of those sharing the same `terms` and `values`. Documents for which every
+// term is missing are not indexed, so they cannot conflict. Fields holding arrays are compared as
+// a whole, whereas Fauna indexes each of their elements, so some conflicts are only reported when
+// the index is built.
+func findUniqueConflicts(ctx context.Context, conn *client.Client, source string, terms [][]string, values [][]string) ([][]f.RefV, int, bool, error) {
+	var selected f.Arr
+	for _, field := range append(append([][]string{}, terms...), values...) {
+		var path f.Arr
+		for _, segment := range field {
+			path = append(path, segment)
+		}
+
+		selected = append(selected, f.Select(path, f.Var("document"), f.Default(f.Null())))
+	}
+
+	project := f.Lambda("ref", f.Let().Bind("document", f.Get(f.Var("ref"))).In(f.Arr{f.Var("ref"), selected}))
+
+	var keys []string
+	groups := map[string][]f.RefV{}
+	scanned := 0
+
+	var after f.Value
+	for scanned < uniqueScanLimit {
+		options := []f.OptionalParameter{f.Size(uniqueScanPageSize)}
+		if after != nil {
+			options = append(options, f.After(after))
+		}
+
+		res, err := conn.Query(ctx, f.Map(f.Paginate(f.Documents(f.Collection(source)), options...), project))
+		if err != nil {
+			return nil, scanned, false, err
+		}
+
+		var entries [][]f.Value
+		if err := res.At(f.ObjKey("data")).Get(&entries); err != nil {
+			return nil, scanned, false, err
+		}
+
+		for _, entry := range entries {
+			if len(entry) != 2 {
+				return nil, scanned, false, fmt.Errorf("unexpected document entry with %d elements", len(entry))
+			}
+
+			var ref f.RefV
+			if err := entry[0].Get(&ref); err != nil {
+				return nil, scanned, false, err
+			}
+
+			var tuple []f.Value
+			if err := entry[1].Get(&tuple); err != nil {
+				return nil, scanned, false, err
+			}
+
+			if len(tuple) != len(terms)+len(values) {
+				return nil, scanned, false, fmt.Errorf("unexpected document entry with %d fields, expected %d", len(tuple), len(terms)+len(values))
+			}
+
+			if len(terms) != 0 && allNull(tuple[:len(terms)]) {
+				continue
+			}
+
+			encoded, err := json.Marshal(tuple)
+			if err != nil {
+				return nil, scanned, false, err
+			}
+
+			key := string(encoded)
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+
+			groups[key] = append(groups[key], ref)
+		}
+
+		scanned += len(entries)
+
+		if after, err = res.At(f.ObjKey("after")).GetValue(); err != nil {
+			after = nil
+			break
+		}
+	}
+
+	var conflicts [][]f.RefV
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			conflicts = append(conflicts, groups[key])
+		}
+	}
+
+	return conflicts, scanned, after == nil, nil
+}
+
+func allNull(values []f.Value) bool {
+	for _, value := range values {
+		if value != (f.NullV{}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describeUniqueConflicts lists the refs of conflicting documents, one line per combination of
+// `terms` and `values`, up to `uniqueConflictSample` refs. The values themselves are left out, as
+// they may hold personal data.
+func describeUniqueConflicts(source string, conflicts [][]f.RefV) string {
+	var lines []string
+	listed := 0
+
+	for _, refs := range conflicts {
+		if listed >= uniqueConflictSample {
+			break
+		}
+
+		var described []string
+		for _, ref := range refs {
+			if listed >= uniqueConflictSample {
+				break
+			}
+
+			described = append(described, fmt.Sprintf("Ref(Collection(%q), %q)", source, ref.ID))
+			listed++
+		}
+
+		lines = append(lines, "- "+strings.Join(described, ", "))
+	}
+
+	if listed < countRefs(conflicts) {
+		lines = append(lines, fmt.Sprintf("And %d more.", countRefs(conflicts)-listed))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func countRefs(conflicts [][]f.RefV) int {
+	count := 0
+	for _, refs := range conflicts {
+		count += len(refs)
+	}
+
+	return count
+}
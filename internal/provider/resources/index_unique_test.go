@@ -0,0 +1,92 @@
+package resources_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider/resources"
+)
+
+func TestCustomizeIndexUniqueness(t *testing.T) {
+	config := map[string]any{
+		"name":   "users_by_email",
+		"source": "users",
+		"unique": true,
+		"terms":  []any{map[string]any{"field": []any{"data", "email"}}},
+	}
+
+	t.Run("duplicates", func(t *testing.T) {
+		conn, stop := documentsServer(t, 3, func(i int) string { return fmt.Sprintf("user%d@example.com", i%2) })
+		defer stop()
+
+		_, err := resources.ResourceIndex().SimpleDiff(context.Background(), nil, terraform.NewResourceConfigRaw(config), conn)
+		if err == nil || !strings.Contains(err.Error(), "among the collection 'users'") || !strings.Contains(err.Error(), `Ref(Collection("users"), "2")`) {
+			t.Errorf("Expected the plan to fail with the conflicting documents, but got: %v", err)
+		}
+	})
+
+	t.Run("large collection", func(t *testing.T) {
+		conn, stop := documentsServer(t, 1500, func(i int) string { return fmt.Sprintf("user%d@example.com", i) })
+		defer stop()
+
+		diff, err := resources.ResourceIndex().SimpleDiff(context.Background(), nil, terraform.NewResourceConfigRaw(config), conn)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "Only the first 1000 documents of the collection 'users' were checked for documents sharing the unique `terms` and `values` of the index 'users_by_email'."
+		if planned := diff.Attributes["planned_impact"]; planned == nil || !strings.Contains(planned.New, expected) || !strings.HasPrefix(planned.New, "Creating the index") {
+			t.Errorf("Expected `planned_impact` to state that the check was partial, but got %#v.", planned)
+		}
+	})
+}
+
+// documentsServer returns a client of a Fauna server whose collections hold `count` documents,
+// the i-th of which has the term `term(i)`, served by pages of 500.
+func documentsServer(t *testing.T, count int, term func(int) string) (*client.Client, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var query map[string]any
+		if err := json.Unmarshal(body, &query); err != nil {
+			t.Error(err)
+		}
+
+		if _, ok := query["count"]; ok {
+			json.NewEncoder(w).Encode(map[string]any{"resource": count})
+			return
+		}
+
+		start := 0
+		if after, ok := query["collection"].(map[string]any)["after"]; ok {
+			fmt.Sscan(fmt.Sprint(after), &start)
+		}
+
+		var entries []any
+		for i := start; i < count && i < start+500; i++ {
+			ref := map[string]any{"@ref": map[string]any{
+				"id":         fmt.Sprint(i),
+				"collection": map[string]any{"@ref": map[string]any{"id": "users", "collection": map[string]any{"@ref": map[string]any{"id": "collections"}}}},
+			}}
+			entries = append(entries, []any{ref, []any{term(i)}})
+		}
+
+		page := map[string]any{"data": entries}
+		if start+500 < count {
+			page["after"] = start + 500
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{"resource": page})
+	}))
+
+	return client.New(client.Config{Secret: "secret", Endpoint: server.URL, HTTP: server.Client()}), server.Close
+}
@@ -0,0 +1,119 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The maximum length of the name of a Fauna resource, in bytes.
+const MaximumNameLength = 255
+
+// The names of resources exposed as FQL v10 top-level identifiers must start with a letter or an
+// underscore, and may only contain letters, digits and underscores.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Types of resources exposed as FQL v10 top-level identifiers.
+var identifierResourceTypes = map[string]bool{
+	"collection": true,
+	"function":   true,
+}
+
+// FQL v10 exposes collections and user-defined functions as top-level identifiers, so they cannot
+// share a name with a keyword or a built-in module.
+var fqlReservedIdentifiers = []string{
+	// Keywords.
+	"at", "else", "false", "if", "isa", "let", "null", "true",
+	// Built-in modules and types.
+	"any", "array", "boolean", "bytes", "collection", "credential", "credentials", "database",
+	"date", "doc", "document", "double", "float", "function", "index", "int", "key", "long",
+	"math", "never", "number", "object", "query", "ref", "role", "set", "string", "time",
+	"token", "transactiontime", "union", "uuid",
+}
+
+// Names reserved for each type of resource, in addition to `BlacklistedResourceNames`.
+var reservedResourceNames = map[string][]string{
+	"collection": fqlReservedIdentifiers,
+	"function":   fqlReservedIdentifiers,
+	"database":   {},
+	"index":      {},
+}
+
+// ReservedNames returns the names that a Fauna resource of type `resourceType` cannot take.
+func ReservedNames(resourceType string) []string {
+	return append(append([]string{}, BlacklistedResourceNames...), reservedResourceNames[resourceType]...)
+}
+
+// CheckName verifies that `name` satisfies all of Fauna's naming rules for a resource of type
+// `resourceType`. Fauna only forbids reserved names and the `%` character, but collections and
+// functions must also be valid FQL v10 identifiers.
+func CheckName(name string, resourceType string) error {
+	if name == "" {
+		return fmt.Errorf("The name of a Fauna '%s' cannot be empty.", resourceType)
+	}
+
+	if len(name) > MaximumNameLength {
+		return fmt.Errorf("The name of a Fauna '%s' cannot be longer than %d characters.", resourceType, MaximumNameLength)
+	}
+
+	if err := CheckNameNotBlacklisted(name, resourceType); err != nil {
+		return err
+	}
+
+	if strings.Contains(name, "%") {
+		return fmt.Errorf("The name of a Fauna '%s' cannot contain '%%', but got '%s'.", resourceType, name)
+	}
+
+	if identifierResourceTypes[resourceType] && !identifierPattern.MatchString(name) {
+		return fmt.Errorf("The name of a Fauna '%s' must start with a letter or an underscore, and may only contain letters, digits and underscores, but got '%s'.", resourceType, name)
+	}
+
+	return nil
+}
+
+// ValidateName returns a function validating the `name` attribute of a resource of type
+// `resourceType` at plan time.
+func ValidateName(resourceType string) schema.SchemaValidateDiagFunc {
+	return func(value any, path cty.Path) diag.Diagnostics {
+		var diags diag.Diagnostics
+
+		name, ok := value.(string)
+		if !ok {
+			return append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid name",
+				Detail:        fmt.Sprintf("Expected the name of a Fauna '%s' to be a string.", resourceType),
+				AttributePath: path,
+			})
+		}
+
+		if err := CheckName(name, resourceType); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity:      diag.Error,
+				Summary:       "Invalid name",
+				Detail:        err.Error(),
+				AttributePath: path,
+			})
+		}
+
+		return diags
+	}
+}
+
+// DescribeNameRules describes the naming rules for a resource of type `resourceType`, to be used
+// in the description of its `name` attribute.
+func DescribeNameRules(resourceType string) string {
+	characters := "Cannot contain `%`"
+	if identifierResourceTypes[resourceType] {
+		characters = "Must start with a letter or an underscore, may only contain letters, digits and underscores"
+	}
+
+	return fmt.Sprintf(
+		"%s, cannot be longer than %d characters, and cannot be one of (case-insensitive): %s.",
+		characters, MaximumNameLength, strings.Join(ReservedNames(resourceType), ", "),
+	)
+}
@@ -0,0 +1,68 @@
+package resources_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider/resources"
+)
+
+func TestCheckName(t *testing.T) {
+	cases := []struct {
+		name         string
+		resourceType string
+		valid        bool
+	}{
+		{"users", "collection", true},
+		{"_users_2", "collection", true},
+		{"users_by_email", "index", true},
+		{"", "collection", false},
+		{"self", "collection", false},
+		{"Self", "function", false},
+		{"documents", "index", false},
+		{"_", "database", false},
+		{"users%", "index", false},
+		{"users%", "database", false},
+		// Only collections and functions must be FQL v10 identifiers.
+		{"users-by-email", "index", true},
+		{"users.email", "database", true},
+		{"users-by-email", "collection", false},
+		{"2users", "collection", false},
+		{"users.email", "function", false},
+		{strings.Repeat("a", resources.MaximumNameLength+1), "collection", false},
+		// FQL v10 identifiers are only reserved for resources exposed as top-level identifiers.
+		{"Math", "collection", false},
+		{"let", "function", false},
+		{"math", "index", true},
+		{"let", "database", true},
+	}
+
+	for _, c := range cases {
+		err := resources.CheckName(c.name, c.resourceType)
+		if c.valid && err != nil {
+			t.Errorf("Expected '%s' to be a valid %s name, but got: %s", c.name, c.resourceType, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("Expected '%s' to be an invalid %s name.", c.name, c.resourceType)
+		}
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	path := cty.GetAttrPath("name")
+
+	if diags := resources.ValidateName("collection")("users", path); diags.HasError() {
+		t.Fatalf("Expected no diagnostics, but got: %v", diags)
+	}
+
+	diags := resources.ValidateName("collection")("self", path)
+	if !diags.HasError() {
+		t.Fatal("Expected an error diagnostic.")
+	}
+
+	if !diags[0].AttributePath.Equals(path) {
+		t.Errorf("Expected the diagnostic to point to '%v', but got '%v'.", path, diags[0].AttributePath)
+	}
+}
@@ -0,0 +1,87 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
+)
+
+// Fauna caches schema for up to 60 seconds, so creating a resource may have to wait for the cache
+// to forget a deleted object with the same name.
+const defaultCreateTimeout = 5 * time.Minute
+
+// createTimeouts describes the timeouts of resources whose creation waits for the schema cache.
+func createTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(defaultCreateTimeout),
+	}
+}
+
+// remainingTime returns how long the operation carried by `ctx` may still run for, which is
+// bounded by the create timeout of the resource.
+func remainingTime(ctx context.Context, data *schema.ResourceData) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+
+	return data.Timeout(schema.TimeoutCreate)
+}
+
+// createObject issues `create`, which creates the object `ref`. While Fauna's schema cache still
+// holds a deleted object with the same name, creation fails because the object already exists
+// even though it does not, so it is retried with backoff until the create timeout.
+func createObject(ctx context.Context, conn *client.Client, data *schema.ResourceData, ref f.Expr, create f.Expr) (f.Value, error) {
+	var res f.Value
+
+	err := resource.RetryContext(ctx, remainingTime(ctx, data), func() *resource.RetryError {
+		var err error
+		res, err = conn.Query(ctx, create)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.As(err, &f.InstanceAlreadyExistsError{}) {
+			return resource.NonRetryableError(err)
+		}
+
+		exists, existsErr := conn.Query(ctx, f.Exists(ref))
+		if existsErr != nil || ParseFaunaValue[bool](exists) {
+			return resource.NonRetryableError(err)
+		}
+
+		tflog.Info(ctx, "Fauna's schema cache still holds a deleted object with the same name, retrying")
+		return resource.RetryableError(err)
+	})
+
+	return res, err
+}
+
+// waitForDefinition waits until reading the object `ref` returns the definition with the
+// timestamp `ts` it was created with, rather than that of a deleted object with the same name
+// that Fauna's schema cache still holds.
+func waitForDefinition(ctx context.Context, conn *client.Client, data *schema.ResourceData, ref f.Expr, ts int64) error {
+	return resource.RetryContext(ctx, remainingTime(ctx, data), func() *resource.RetryError {
+		res, err := conn.Query(ctx, f.Select("ts", f.Get(ref)))
+		if IsNotFound(err) {
+			return resource.RetryableError(err)
+		}
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		if current := ParseFaunaValue[int64](res); current < ts {
+			return resource.RetryableError(fmt.Errorf("Fauna returned a stale definition with the timestamp %d instead of %d.", current, ts))
+		}
+
+		return nil
+	})
+}
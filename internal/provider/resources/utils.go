@@ -1,18 +1,26 @@
 package resources
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
 	f "github.com/fauna/faunadb-go/v5/faunadb"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/client"
 )
 
 var BlacklistedResourceNames = []string{"events", "sets", "self", "documents", "_"}
 
 func CheckNameNotBlacklisted(name string, resourceType string) error {
 	nameTrimmed := strings.TrimSpace(name)
-	for _, blacklistedResourceName := range BlacklistedResourceNames {
-		if nameTrimmed == blacklistedResourceName {
+	for _, blacklistedResourceName := range ReservedNames(resourceType) {
+		if strings.EqualFold(nameTrimmed, blacklistedResourceName) {
 			return fmt.Errorf("The name of a Fauna '%s' cannot be '%s'.", resourceType, name)
 		}
 	}
@@ -20,6 +28,122 @@ func CheckNameNotBlacklisted(name string, resourceType string) error {
 	return nil
 }
 
+// withOperation wraps a CRUD function of a resource of type `resourceType`, attributing the queries
+// it issues to `operation` on that resource and tracing it.
+func withOperation(resourceType string, operation client.Operation, fn func(context.Context, *schema.ResourceData, any) diag.Diagnostics) func(context.Context, *schema.ResourceData, any) diag.Diagnostics {
+	return func(ctx context.Context, data *schema.ResourceData, meta any) diag.Diagnostics {
+		name := data.Get("name").(string)
+
+		request := client.Request{
+			ResourceType: resourceType,
+			ObjectName:   name,
+			Operation:    operation,
+		}
+
+		conn, ok := meta.(*client.Client)
+		if !ok {
+			return fn(client.WithRequest(ctx, request), data, meta)
+		}
+
+		if operation != client.OperationRead {
+			if diags := checkMutationAllowed(resourceType, operation, data, conn); diags.HasError() {
+				return diags
+			}
+		}
+
+		before := conn.Metrics(request.Object())
+
+		ctx, span := conn.StartOperation(ctx, request)
+		diags := fn(ctx, data, meta)
+
+		consumed := conn.Metrics(request.Object()).Sub(before)
+		conn.EndOperation(ctx, span, consumed, diagnosticsError(diags))
+		writeMetrics(ctx, conn)
+
+		if !conn.ReportOperationMetrics() || operation == client.OperationRead {
+			return diags
+		}
+
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Fauna query metrics",
+			Detail:   fmt.Sprintf("The %s of %s '%s' consumed %s. The provider has consumed %s in total during this run.", operation, resourceType, name, consumed, conn.TotalMetrics()),
+		})
+	}
+}
+
+// writeMetrics updates the metrics file with the queries issued so far. The metrics file is a
+// report, so failing to write it does not fail the operation.
+func writeMetrics(ctx context.Context, conn *client.Client) {
+	if err := conn.WriteMetrics(); err != nil {
+		tflog.Warn(ctx, err.Error())
+	}
+}
+
+// diagnosticsError summarises the errors among `diags`, if there are any.
+func diagnosticsError(diags diag.Diagnostics) error {
+	var summaries []string
+	for _, diagnostic := range diags {
+		if diagnostic.Severity == diag.Error {
+			summaries = append(summaries, diagnostic.Summary)
+		}
+	}
+
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(summaries, "; "))
+}
+
+// checkMutationAllowed verifies that the provider's settings allow `operation` to modify a resource.
+func checkMutationAllowed(resourceType string, operation client.Operation, data *schema.ResourceData, conn *client.Client) diag.Diagnostics {
+	name := data.Get("name").(string)
+
+	if conn.ReadOnly() {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Provider is read-only",
+			Detail:   fmt.Sprintf("Cannot %s %s '%s': the provider is read-only (`read_only = true`), so it can only read resources. Unset `read_only` to apply changes.", operation, resourceType, name),
+		}}
+	}
+
+	// Resources are modified in the database the secret is scoped to, except for databases, which
+	// modify themselves.
+	databases := []string{conn.Database()}
+	if resourceType == "fauna_database" {
+		previousName, _ := data.GetChange("name")
+
+		databases = []string{client.JoinDatabase(conn.Database(), name)}
+		if previousName := previousName.(string); previousName != "" && previousName != name {
+			databases = append(databases, client.JoinDatabase(conn.Database(), previousName))
+		}
+	}
+
+	var diags diag.Diagnostics
+	for _, database := range databases {
+		if err := conn.CheckDatabase(database); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Database not allowed",
+				Detail:   fmt.Sprintf("Cannot %s %s '%s': %s", operation, resourceType, name, err),
+			})
+		}
+	}
+
+	return diags
+}
+
+// DeletionProtectionDiagnostics explains that a resource of type `resourceType` cannot be deleted
+// while its `deletion_protection` is enabled.
+func DeletionProtectionDiagnostics(resourceType string, name string) diag.Diagnostics {
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Deletion protection enabled",
+		Detail:   fmt.Sprintf("Cannot delete %s '%s' because its `deletion_protection` is enabled. Set `deletion_protection = false` and apply before destroying it.", resourceType, name),
+	}}
+}
+
 func GetProperty[U any](obj f.ObjectV, propName string, def U) (U, bool) {
 	if value, ok := obj[propName]; ok {
 		return ParseFaunaValue[U](value), true
@@ -33,3 +157,59 @@ func ParseFaunaValue[U any](obj f.Value) U {
 	obj.Get(&parsed)
 	return parsed
 }
+
+// GetChangedProperties builds the object to pass to `f.Update()` out of the properties that have
+// changed since the last apply.
+//
+// Since `f.Update()` merges objects rather than replacing them, keys removed from `data` (or from
+// the provider's `default_data`) are sent as `null`, and so are the properties listed in
+// `nullable` once they have been unset.
+func GetChangedProperties(data *schema.ResourceData, properties []string, nullable []string) f.Obj {
+	object := f.Obj{}
+	for _, property := range properties {
+		// The metadata sent to Fauna includes the provider's `default_data`, which may change
+		// independently of `data`.
+		if property == "data" {
+			if data.HasChanges("data", "data_all") {
+				object[property] = GetDataChange(data)
+			}
+			continue
+		}
+
+		if !data.HasChange(property) {
+			continue
+		}
+
+		if contains(nullable, property) && unsetInConfig(data, property) {
+			object[property] = f.Null()
+			continue
+		}
+
+		object[property] = data.Get(property)
+	}
+
+	return object
+}
+
+// unsetInConfig reports whether `property` is left out of the configuration. Unlike `GetOk`, it
+// tells an explicit zero value, such as `history_days = 0`, from an unset property. Without a
+// configuration to refer to, zero values are treated as unset.
+func unsetInConfig(data *schema.ResourceData, property string) bool {
+	config := data.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() || !config.Type().HasAttribute(property) {
+		_, ok := data.GetOk(property)
+		return !ok
+	}
+
+	return config.GetAttr(property).IsNull()
+}
+
+func contains(values []string, value string) bool {
+	for _, value_ := range values {
+		if value_ == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// The text substituted for secrets in logs and error messages.
+const redacted = "[REDACTED]"
+
+// ScopeSecret derives a secret scoped to the child database at the slash-separated `database`
+// path, with the permissions of the role `role`. An empty `database` leaves `secret` as is.
+func ScopeSecret(secret string, database string, role string) (string, error) {
+	database = strings.Trim(database, "/")
+	if database == "" {
+		return secret, nil
+	}
+
+	if strings.Contains(secret, ":") {
+		return "", fmt.Errorf("Cannot scope a secret that is already scoped to a database to the database '%s'.", database)
+	}
+
+	return fmt.Sprintf("%s:%s:%s", secret, database, role), nil
+}
+
+// ParseScopedSecret returns the database path and role that `secret` is scoped to, if any.
+func ParseScopedSecret(secret string) (database string, role string) {
+	parts := strings.SplitN(secret, ":", 3)
+	if len(parts) < 2 {
+		return "", ""
+	}
+
+	if len(parts) == 3 {
+		role = parts[2]
+	}
+
+	return parts[1], role
+}
+
+// ReadSecretFile reads a secret from the file at `path`, ignoring surrounding whitespace.
+func ReadSecretFile(path string) (string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read the secret file: %s", err)
+	}
+
+	secret := strings.TrimSpace(string(contents))
+	if secret == "" {
+		return "", fmt.Errorf("The secret file '%s' is empty.", path)
+	}
+
+	return secret, nil
+}
+
+// RunSecretCommand executes `command` and reads a secret from its standard output, ignoring
+// surrounding whitespace. The command is killed if it does not complete within `timeout`.
+//
+// The secret never appears in the returned error, even if the command echoes it to its standard
+// error before failing.
+func RunSecretCommand(ctx context.Context, command []string, timeout time.Duration) (string, error) {
+	if len(command) == 0 || command[0] == "" {
+		return "", errors.New("The secret command is empty.")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	secret := strings.TrimSpace(stdout.String())
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("The secret command '%s' did not complete within %s.", command[0], timeout)
+	}
+
+	if err != nil {
+		message := fmt.Sprintf("The secret command '%s' failed: %s", command[0], err)
+		if output := strings.TrimSpace(stderr.String()); output != "" {
+			message = fmt.Sprintf("%s\n\n%s", message, Redact(output, secret))
+		}
+
+		return "", errors.New(message)
+	}
+
+	if secret == "" {
+		return "", fmt.Errorf("The secret command '%s' did not print a secret.", command[0])
+	}
+
+	return secret, nil
+}
+
+// Redact replaces every occurrence of each of `secrets` in `text`.
+func Redact(text string, secrets ...string) string {
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		text = strings.ReplaceAll(text, secret, redacted)
+	}
+
+	return text
+}
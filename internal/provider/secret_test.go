@@ -0,0 +1,76 @@
+package provider_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider"
+)
+
+func TestScopeSecret(t *testing.T) {
+	secret, err := provider.ScopeSecret("fnSecret", "/app/staging/", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret != "fnSecret:app/staging:admin" {
+		t.Errorf("Unexpected scoped secret '%s'.", secret)
+	}
+
+	if secret, _ := provider.ScopeSecret("fnSecret", "", "admin"); secret != "fnSecret" {
+		t.Errorf("Expected an unscoped secret to be left as is, but got '%s'.", secret)
+	}
+
+	if _, err := provider.ScopeSecret("fnSecret:app:admin", "staging", "admin"); err == nil {
+		t.Error("Expected scoping an already scoped secret to fail.")
+	}
+}
+
+func TestReadSecretFile(t *testing.T) {
+	secret, err := provider.ReadSecretFile(writeConfigFile(t, "secret", "  fnSecret\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret != "fnSecret" {
+		t.Errorf("Unexpected secret '%s'.", secret)
+	}
+
+	if _, err := provider.ReadSecretFile(writeConfigFile(t, "secret", "\n")); err == nil {
+		t.Error("Expected an empty secret file to be rejected.")
+	}
+}
+
+func TestRunSecretCommand(t *testing.T) {
+	ctx := context.Background()
+
+	secret, err := provider.RunSecretCommand(ctx, []string{"sh", "-c", "echo '  fnSecret  '"}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret != "fnSecret" {
+		t.Errorf("Unexpected secret '%s'.", secret)
+	}
+
+	_, err = provider.RunSecretCommand(ctx, []string{"sh", "-c", "echo fnSecret; echo 'leaked fnSecret' >&2; exit 1"}, time.Second)
+	if err == nil {
+		t.Fatal("Expected a failing command to be reported.")
+	}
+	if strings.Contains(err.Error(), "fnSecret") {
+		t.Errorf("Expected the secret to be redacted from the error, but got: %s", err)
+	}
+
+	if _, err := provider.RunSecretCommand(ctx, []string{"sleep", "5"}, 50*time.Millisecond); err == nil || !strings.Contains(err.Error(), "did not complete") {
+		t.Errorf("Expected the command to time out, but got: %v", err)
+	}
+}
+
+func TestParseScopedSecret(t *testing.T) {
+	if database, role := provider.ParseScopedSecret("fnSecret:app/staging:server"); database != "app/staging" || role != "server" {
+		t.Errorf("Unexpected scope '%s' with role '%s'.", database, role)
+	}
+
+	if database, role := provider.ParseScopedSecret("fnSecret"); database != "" || role != "" {
+		t.Errorf("Expected an unscoped secret, but got scope '%s' with role '%s'.", database, role)
+	}
+}
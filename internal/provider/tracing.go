@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// The name of the service spans are reported for.
+const tracingServiceName = "terraform-provider-fauna"
+
+// The path spans are sent to on an OTLP endpoint whose URL does not specify one.
+const defaultOTLPTracesPath = "/v1/traces"
+
+// TracingConfig holds the settings of the exporters that spans are sent to.
+type TracingConfig struct {
+	// The URL of the OTLP/HTTP endpoint to send spans to, if any.
+	OTLPEndpoint string
+	// Headers sent with every request to the OTLP endpoint, such as credentials.
+	OTLPHeaders map[string]string
+	// The path of the file to append spans to as JSON, if any.
+	File string
+}
+
+// fileExporter writes spans to a file, which it closes once shut down.
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (exporter fileExporter) Shutdown(ctx context.Context) error {
+	err := exporter.SpanExporter.Shutdown(ctx)
+	if closeErr := exporter.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// The tracer providers of every configured provider, which are shut down along with the plugin.
+var tracerProviders struct {
+	sync.Mutex
+	providers []*sdktrace.TracerProvider
+}
+
+// RegisterTracerProvider shuts `tracerProvider` down along with the plugin.
+func RegisterTracerProvider(tracerProvider *sdktrace.TracerProvider) {
+	tracerProviders.Lock()
+	defer tracerProviders.Unlock()
+
+	tracerProviders.providers = append(tracerProviders.providers, tracerProvider)
+}
+
+// Shutdown exports the spans still batched by the tracer providers of every configured provider,
+// then closes their exporters. It is called once the plugin has stopped serving Terraform, which
+// gives no other notice of the end of a run.
+func Shutdown(ctx context.Context) error {
+	tracerProviders.Lock()
+	defer tracerProviders.Unlock()
+
+	var failures []string
+	for _, tracerProvider := range tracerProviders.providers {
+		if err := tracerProvider.Shutdown(ctx); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	tracerProviders.providers = nil
+
+	if len(failures) != 0 {
+		return fmt.Errorf("Failed to export traces: %s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// NewTracerProvider creates a tracer provider exporting spans as configured, or nil if no exporter
+// is configured.
+func NewTracerProvider(ctx context.Context, config TracingConfig) (*sdktrace.TracerProvider, error) {
+	var options []sdktrace.TracerProviderOption
+
+	if config.OTLPEndpoint != "" {
+		endpoint, err := url.Parse(config.OTLPEndpoint)
+		if err != nil || endpoint.Host == "" {
+			return nil, fmt.Errorf("The OTLP endpoint '%s' is not a valid URL.", config.OTLPEndpoint)
+		}
+
+		path := endpoint.Path
+		if strings.Trim(path, "/") == "" {
+			path = defaultOTLPTracesPath
+		}
+
+		otlpOptions := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(endpoint.Host),
+			otlptracehttp.WithURLPath(path),
+			otlptracehttp.WithHeaders(config.OTLPHeaders),
+		}
+
+		if endpoint.Scheme == "http" {
+			otlpOptions = append(otlpOptions, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, otlpOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create the OTLP exporter: %s", err)
+		}
+
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	if config.File != "" {
+		file, err := os.OpenFile(config.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to open the trace file '%s': %s", config.File, err)
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("Failed to create the trace file exporter: %s", err)
+		}
+
+		options = append(options, sdktrace.WithBatcher(fileExporter{exporter, file}))
+	}
+
+	if len(options) == 0 {
+		return nil, nil
+	}
+
+	resource, err := sdkresource.Merge(sdkresource.Default(), sdkresource.NewSchemaless(semconv.ServiceName(tracingServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.NewTracerProvider(append(options, sdktrace.WithResource(resource))...), nil
+}
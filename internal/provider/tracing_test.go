@@ -0,0 +1,48 @@
+package provider_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	ctx := context.Background()
+
+	if tracerProvider, err := provider.NewTracerProvider(ctx, provider.TracingConfig{}); err != nil || tracerProvider != nil {
+		t.Errorf("Expected no tracer provider without an exporter, but got %v: %v", tracerProvider, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "traces.json")
+
+	tracerProvider, err := provider.NewTracerProvider(ctx, provider.TracingConfig{File: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := tracerProvider.Tracer("test").Start(ctx, "fauna_collection create")
+	span.End()
+
+	// Spans are batched until the plugin is shut down.
+	provider.RegisterTracerProvider(tracerProvider)
+	if err := provider.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(contents), `"Name":"fauna_collection create"`) || !strings.Contains(string(contents), "terraform-provider-fauna") {
+		t.Errorf("Expected the span to be written to the file, but got: %s", contents)
+	}
+
+	if _, err := provider.NewTracerProvider(ctx, provider.TracingConfig{OTLPEndpoint: "localhost"}); err == nil {
+		t.Error("Expected an OTLP endpoint without a host to be rejected.")
+	}
+}
@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// TransportConfig holds the settings of the HTTP client used to send queries to Fauna.
+type TransportConfig struct {
+	// The maximum duration of a request, including reading the response. Zero means no limit.
+	Timeout time.Duration
+	// The URL of the proxy to send requests through. Defaults to the proxy given by the
+	// `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.
+	ProxyURL string
+	// PEM-encoded certificates of the authorities to trust in addition to those of the system.
+	CACertPEM []byte
+	// Whether to skip the verification of the endpoint's TLS certificate.
+	InsecureSkipVerify bool
+	// The maximum number of idle connections kept open. Zero means the Go default.
+	MaxIdleConnections int
+}
+
+// IsDefault reports whether the settings are all left at their defaults, in which case the Fauna
+// client is best left to create its own HTTP client.
+func (config TransportConfig) IsDefault() bool {
+	return config.Timeout == 0 && config.ProxyURL == "" && len(config.CACertPEM) == 0 && !config.InsecureSkipVerify && config.MaxIdleConnections == 0
+}
+
+// NewHTTPClient creates an HTTP client with the given settings.
+func NewHTTPClient(config TransportConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("The proxy URL '%s' is not a valid URL: %s", config.ProxyURL, err)
+		}
+
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if config.MaxIdleConnections != 0 {
+		transport.MaxIdleConns = config.MaxIdleConnections
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnections
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if len(config.CACertPEM) != 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(config.CACertPEM) {
+			return nil, errors.New("The CA certificate does not contain any valid PEM-encoded certificate.")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport, Timeout: config.Timeout}, nil
+}
+
+// ReadCACertFile reads PEM-encoded certificates from the file at `path`.
+func ReadCACertFile(path string) ([]byte, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the CA certificate file: %s", err)
+	}
+
+	return contents, nil
+}
@@ -0,0 +1,63 @@
+package provider_test
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wordcollector/terraform-provider-fauna/internal/provider"
+)
+
+func TestNewHTTPClient_caCert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	untrusting, err := provider.NewHTTPClient(provider.TransportConfig{Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := untrusting.Get(server.URL); err == nil {
+		t.Error("Expected a certificate signed by an unknown authority to be rejected.")
+	}
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	trusting, err := provider.NewHTTPClient(provider.TransportConfig{Timeout: time.Second, CACertPEM: cert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trusting.Get(server.URL); err != nil {
+		t.Errorf("Expected the CA certificate to be trusted, but got: %s", err)
+	}
+
+	insecure, err := provider.NewHTTPClient(provider.TransportConfig{Timeout: time.Second, InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := insecure.Get(server.URL); err != nil {
+		t.Errorf("Expected certificate verification to be skipped, but got: %s", err)
+	}
+}
+
+func TestNewHTTPClient_invalid(t *testing.T) {
+	if _, err := provider.NewHTTPClient(provider.TransportConfig{CACertPEM: []byte("not a certificate")}); err == nil {
+		t.Error("Expected an invalid CA certificate to be rejected.")
+	}
+}
+
+func TestNewHTTPClient_timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client, err := provider.NewHTTPClient(provider.TransportConfig{Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get(server.URL); err == nil {
+		t.Error("Expected the request to time out.")
+	}
+}
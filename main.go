@@ -1,6 +1,10 @@
 package main
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 
 	"github.com/wordcollector/terraform-provider-fauna/internal/provider"
@@ -9,8 +13,21 @@ import (
 // Generate the Terraform provider documentation using `tfplugindocs`:
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
 
+// How long to wait for traces to be exported once Terraform stops serving the plugin, which it
+// kills after two seconds.
+const shutdownTimeout = 1500 * time.Millisecond
+
 func main() {
 	opts := &plugin.ServeOpts{ProviderFunc: provider.Provider}
 
 	plugin.Serve(opts)
+
+	// Terraform stops serving the plugin once the run completes, and kills it shortly after, so
+	// the spans still batched are exported in the meantime.
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := provider.Shutdown(ctx); err != nil {
+		log.Printf("[WARN] %s", err)
+	}
 }